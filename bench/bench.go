@@ -0,0 +1,84 @@
+// Package bench holds the prepared benchmarks of the benchmark lab, which
+// measure what interfaces cost compared with direct calls and generics.
+// They run inside the explorer with testing.Benchmark, so no test files or
+// go command are needed.
+package bench
+
+import (
+	"testing"
+	"time"
+)
+
+// Benchmark is one measured way of doing the work of a suite
+type Benchmark struct {
+	ID   string
+	Name string
+	Code string // the call or statement being measured
+	F    func(b *testing.B)
+}
+
+// Suite compares several ways of doing the same work. The first benchmark
+// is the baseline the others are compared with.
+type Suite struct {
+	ID          string
+	Title       string
+	Benchmarks  []*Benchmark
+	Explanation string
+}
+
+// Result is the measurement of one benchmark
+type Result struct {
+	Benchmark   *Benchmark
+	N           int
+	NsPerOp     float64
+	AllocsPerOp int64
+	BytesPerOp  int64
+}
+
+// Relative returns how many times as long the result took as the baseline
+func (r Result) Relative(baseline Result) float64 {
+	if baseline.NsPerOp == 0 {
+		return 0
+	}
+	return r.NsPerOp / baseline.NsPerOp
+}
+
+// Suites are the benchmarks of the lab, in the order they are offered
+var Suites = []*Suite{dispatchSuite, stackSuite}
+
+// LookupSuite finds a suite by its ID
+func LookupSuite(id string) *Suite {
+	for _, s := range Suites {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// Run measures every benchmark of the suite, calling started before each
+// one since a benchmark takes about a second
+func (s *Suite) Run(started func(*Benchmark)) []Result {
+	results := make([]Result, 0, len(s.Benchmarks))
+	for _, b := range s.Benchmarks {
+		if started != nil {
+			started(b)
+		}
+		results = append(results, measure(b))
+	}
+	return results
+}
+
+func measure(b *Benchmark) Result {
+	r := testing.Benchmark(func(tb *testing.B) {
+		tb.ReportAllocs()
+		b.F(tb)
+	})
+	result := Result{Benchmark: b, N: r.N, AllocsPerOp: r.AllocsPerOp(), BytesPerOp: r.AllocedBytesPerOp()}
+	if r.N > 0 {
+		// BenchmarkResult.NsPerOp rounds down to whole nanoseconds, which
+		// hides the difference between calls that take less than one
+		result.NsPerOp = float64(r.T) / float64(time.Duration(r.N))
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package bench
+
+import "testing"
+
+// anyStack is the Stack of the Empty Interface lesson: it holds values of
+// any type in interface{} slots
+type anyStack struct {
+	items []interface{}
+}
+
+func (s *anyStack) Push(item interface{}) {
+	s.items = append(s.items, item)
+}
+
+func (s *anyStack) Pop() (interface{}, bool) {
+	if len(s.items) == 0 {
+		return nil, false
+	}
+	index := len(s.items) - 1
+	item := s.items[index]
+	s.items = s.items[:index]
+	return item, true
+}
+
+// intStack is the same stack written for one type
+type intStack struct {
+	items []int
+}
+
+func (s *intStack) Push(item int) {
+	s.items = append(s.items, item)
+}
+
+func (s *intStack) Pop() (int, bool) {
+	if len(s.items) == 0 {
+		return 0, false
+	}
+	index := len(s.items) - 1
+	item := s.items[index]
+	s.items = s.items[:index]
+	return item, true
+}
+
+// genericStack is the type-safe alternative the Empty Interface lesson
+// mentions: one implementation, checked for each element type
+type genericStack[T any] struct {
+	items []T
+}
+
+func (s *genericStack[T]) Push(item T) {
+	s.items = append(s.items, item)
+}
+
+func (s *genericStack[T]) Pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	index := len(s.items) - 1
+	item := s.items[index]
+	s.items = s.items[:index]
+	return item, true
+}
+
+var stackSink int
+
+// Each operation pushes a value and pops it again. The values start above
+// 255 because Go keeps preallocated interface values for small integers.
+const stackBase = 1000
+
+var stackSuite = &Suite{
+	ID:    "stack",
+	Title: "A stack of ints: interface{} vs typed vs generic",
+	Benchmarks: []*Benchmark{
+		{
+			ID:   "any",
+			Name: "interface{} Stack",
+			Code: "s.Push(i); v, _ := s.Pop(); v.(int)",
+			F: func(b *testing.B) {
+				var s anyStack
+				for i := 0; i < b.N; i++ {
+					s.Push(stackBase + i)
+					v, _ := s.Pop()
+					stackSink = v.(int)
+				}
+			},
+		},
+		{
+			ID:   "typed",
+			Name: "Typed IntStack",
+			Code: "s.Push(i); v, _ := s.Pop()",
+			F: func(b *testing.B) {
+				var s intStack
+				for i := 0; i < b.N; i++ {
+					s.Push(stackBase + i)
+					stackSink, _ = s.Pop()
+				}
+			},
+		},
+		{
+			ID:   "generic",
+			Name: "Generic Stack[int]",
+			Code: "s.Push(i); v, _ := s.Pop()",
+			F: func(b *testing.B) {
+				var s genericStack[int]
+				for i := 0; i < b.N; i++ {
+					s.Push(stackBase + i)
+					stackSink, _ = s.Pop()
+				}
+			},
+		},
+	},
+	Explanation: `Storing an int in an interface{} needs a pointer to the value, so Push
+copies the int to the heap: one allocation of 8 bytes per push. The garbage
+collector has to clean those up later, and Pop needs a type assertion to get
+the int back, which panics at run time if the stack held something else.
+
+The typed stack keeps the ints directly in its slice. Nothing is allocated
+once the slice has grown, and the compiler checks every Push and Pop.
+
+The generic stack is compiled for ints like the typed one, so it stores the
+values directly and allocates nothing, while still being written only once.
+This is why the Empty Interface lesson calls generics the more type-safe
+alternative: they are also the faster one for containers of values.`,
+}
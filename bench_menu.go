@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-interface-enum-explorer/bench"
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/utils"
+)
+
+// benchmarkLab lets the learner run the prepared benchmarks and shows the
+// measurements with an explanation of what they mean
+func benchmarkLab(scanner *bufio.Scanner) {
+	for {
+		clearScreen()
+		utils.PrintColoredTitle(i18n.T("bench.menu_title"), utils.ColorCyan)
+		fmt.Println(i18n.T("bench.intro"))
+		fmt.Println()
+		for i, suite := range bench.Suites {
+			fmt.Printf("%d. %s\n", i+1, suite.Title)
+		}
+		fmt.Println("a. " + i18n.T("bench.all"))
+		fmt.Println("b. " + i18n.T("menu.back"))
+
+		fmt.Print("\n" + i18n.T("prompt.choice"))
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
+
+		if handleSearch(scanner, choice) {
+			continue
+		}
+		var suites []*bench.Suite
+		switch index, err := strconv.Atoi(choice); {
+		case choice == "b" || choice == "B":
+			return
+		case choice == "a" || choice == "A":
+			suites = bench.Suites
+		case err == nil && index >= 1 && index <= len(bench.Suites):
+			suites = bench.Suites[index-1 : index]
+		default:
+			fmt.Println(i18n.T("error.invalid_selection"))
+			utils.PressEnterToContinue()
+			continue
+		}
+
+		var lines []string
+		for _, suite := range suites {
+			lines = append(lines, benchmarkLines(suite, runSuite(suite))...)
+		}
+		clearScreen()
+		doc := &utils.Document{Lines: lines}
+		doc.Show()
+		utils.PressEnterToContinue()
+	}
+}
+
+// runSuite runs the benchmarks of a suite, saying which one is running
+// since each takes about a second
+func runSuite(suite *bench.Suite) []bench.Result {
+	return suite.Run(func(b *bench.Benchmark) {
+		fmt.Println(utils.Colorize(utils.ColorGray, i18n.T("bench.running", b.Name)))
+	})
+}
+
+// benchmarkLines lays out the results of a suite as a table, or as one
+// sentence per benchmark when a screen reader is reading it, followed by
+// the explanation of the results
+func benchmarkLines(suite *bench.Suite, results []bench.Result) []string {
+	var lines []string
+	if utils.Accessible() {
+		lines = append(lines, suite.Title, "")
+	} else {
+		rule := utils.Colorize(utils.ColorCyan, "===================================")
+		lines = append(lines, rule, utils.Colorize(utils.ColorCyan, suite.Title), rule, "")
+	}
+
+	nameWidth := len(i18n.T("bench.column_name"))
+	for _, r := range results {
+		if len(r.Benchmark.Name) > nameWidth {
+			nameWidth = len(r.Benchmark.Name)
+		}
+	}
+	if !utils.Accessible() {
+		header := fmt.Sprintf("%-*s %10s %10s %8s %10s", nameWidth, i18n.T("bench.column_name"),
+			"ns/op", "allocs/op", "B/op", i18n.T("bench.column_relative"))
+		lines = append(lines, utils.Colorize(utils.ColorMagenta, header))
+	}
+	for _, r := range results {
+		relative := r.Relative(results[0])
+		if utils.Accessible() {
+			lines = append(lines, i18n.T("a11y.benchmark", r.Benchmark.Name, r.NsPerOp, r.AllocsPerOp, r.BytesPerOp, relative))
+			continue
+		}
+		row := fmt.Sprintf("%-*s %10.2f %10d %8d %9.1f×", nameWidth, r.Benchmark.Name, r.NsPerOp, r.AllocsPerOp, r.BytesPerOp, relative)
+		if r.AllocsPerOp > 0 {
+			row = utils.Colorize(utils.ColorYellow, row)
+		}
+		lines = append(lines, row)
+	}
+
+	lines = append(lines, "", utils.Colorize(utils.ColorMagenta, i18n.T("bench.measured")))
+	for _, b := range suite.Benchmarks {
+		lines = append(lines, "  "+b.Name+": "+utils.Colorize(utils.ColorGray, b.Code))
+	}
+	lines = append(lines, "", utils.Colorize(utils.ColorMagenta, i18n.T("bench.explanation")))
+	lines = append(lines, strings.Split(suite.Explanation, "\n")...)
+	lines = append(lines, "", utils.Colorize(utils.ColorGray, i18n.T("bench.note")), "")
+	return lines
+}
@@ -0,0 +1,151 @@
+// Package checks holds static checks for the interface mistakes the lessons
+// warn about. They parse and type-check a package with go/types and report
+// each problem with an excerpt of the code around it.
+package checks
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-interface-enum-explorer/snippet"
+)
+
+// Finding is one problem a check found
+type Finding struct {
+	Check   string // ID of the check, such as "typed-nil"
+	Pos     token.Position
+	Func    string // the function the problem is in
+	Message string
+	Fix     string
+	Excerpt []ExcerptLine
+}
+
+// ExcerptLine is a line of the code around a finding. The line the finding
+// is about is marked.
+type ExcerptLine struct {
+	Number int
+	Text   string
+	Marked bool
+}
+
+// excerptContext is the number of lines shown before and after the marked
+// line of an excerpt
+const excerptContext = 2
+
+// Dir checks the Go package in dir. Test files are left out. Type errors do
+// not stop the checks, but a file that cannot be parsed does.
+func Dir(dir string) ([]Finding, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	sources := make(map[string]string)
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		sources[name] = string(src)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("checks: no Go files in %s", dir)
+	}
+	return run(fset, files, sources, 0), nil
+}
+
+// Snippet checks lesson code, which is completed into a program first the
+// way the sandbox does. Positions and excerpts refer to the code as given.
+func Snippet(code string) ([]Finding, error) {
+	code = strings.Trim(code, "\n")
+	program := snippet.Program(code)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", program, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	findings := run(fset, []*ast.File{file}, map[string]string{"main.go": code}, snippet.Offset(code))
+	return findings, nil
+}
+
+// check is the signature of a check: it looks at the type-checked files
+// and reports what it finds
+type check func(p *pass) []Finding
+
+// checkList lists the checks run on every package
+var checkList = []check{typedNil, sealedSwitch}
+
+// pass is what a check gets to look at
+type pass struct {
+	fset  *token.FileSet
+	files []*ast.File
+	pkg   *types.Package
+	info  *types.Info
+}
+
+// run type-checks the files and runs every check on them. offset is the
+// number of lines that were added before the sources, as for a snippet.
+func run(fset *token.FileSet, files []*ast.File, sources map[string]string, offset int) []Finding {
+	info := &types.Info{
+		Types:  make(map[ast.Expr]types.TypeAndValue),
+		Defs:   make(map[*ast.Ident]types.Object),
+		Uses:   make(map[*ast.Ident]types.Object),
+		Scopes: make(map[ast.Node]*types.Scope),
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// Keep going after type errors, so code with a mistake elsewhere
+		// is still checked
+		Error: func(error) {},
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, info)
+
+	p := &pass{fset: fset, files: files, pkg: pkg, info: info}
+	var findings []Finding
+	for _, c := range checkList {
+		findings = append(findings, c(p)...)
+	}
+	for i := range findings {
+		f := &findings[i]
+		f.Pos.Line -= offset
+		f.Excerpt = excerpt(sources[f.Pos.Filename], f.Pos.Line)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Pos, findings[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return findings
+}
+
+// excerpt returns the lines of src around line, with line marked
+func excerpt(src string, line int) []ExcerptLine {
+	lines := strings.Split(src, "\n")
+	var out []ExcerptLine
+	for n := line - excerptContext; n <= line+excerptContext; n++ {
+		if n < 1 || n > len(lines) {
+			continue
+		}
+		out = append(out, ExcerptLine{Number: n, Text: strings.TrimRight(lines[n-1], " \t\r"), Marked: n == line})
+	}
+	return out
+}
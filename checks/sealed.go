@@ -0,0 +1,205 @@
+package checks
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Sealed reports whether an interface is sealed: it has an unexported
+// method, such as isAnimal(), so only types in its own package can
+// implement it and the package knows every implementation. An interface
+// that embeds a sealed interface is sealed too.
+func Sealed(iface *types.Interface) bool {
+	return sealingMethod(iface) != nil
+}
+
+// sealedIn returns the package a sealed interface is sealed in, the one
+// its unexported method belongs to. Only types declared there can
+// implement it. This is the package that declares the interface, unless
+// the interface gets the method by embedding a sealed interface of another
+// package.
+func sealedIn(iface *types.Interface) *types.Package {
+	if m := sealingMethod(iface); m != nil {
+		return m.Pkg()
+	}
+	return nil
+}
+
+// sealingMethod returns an unexported method of iface, or nil. The method
+// set includes the methods of the interfaces iface embeds, so an interface
+// such as
+//
+//	type Polygon interface {
+//		Shape
+//		Corners() int
+//	}
+//
+// is sealed by the unexported method of Shape.
+func sealingMethod(iface *types.Interface) types.Object {
+	methods := types.NewMethodSet(iface)
+	for i := 0; i < methods.Len(); i++ {
+		if m := methods.At(i).Obj(); !m.Exported() {
+			return m
+		}
+	}
+	return nil
+}
+
+// Implementations returns the named types of pkg that implement a sealed
+// interface, directly or through a pointer, sorted by name. Interface types
+// are left out, since they only describe other implementations.
+func Implementations(pkg *types.Package, iface *types.Interface) []*types.Named {
+	var impls []*types.Named
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || types.IsInterface(named) {
+			continue
+		}
+		if types.Implements(named, iface) || types.Implements(types.NewPointer(named), iface) {
+			impls = append(impls, named)
+		}
+	}
+	return impls
+}
+
+// sealedSwitch reports type switches over a sealed interface that do not
+// have a case for every implementation. A default case does not count: it
+// is exactly what would silently swallow a new implementation.
+func sealedSwitch(p *pass) []Finding {
+	var findings []Finding
+	for _, file := range p.files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			findings = append(findings, sealedSwitches(p, fn.Name.Name, fn.Body)...)
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if lit, ok := n.(*ast.FuncLit); ok {
+					findings = append(findings, sealedSwitches(p, "a function literal in "+fn.Name.Name, lit.Body)...)
+				}
+				return true
+			})
+		}
+	}
+	return findings
+}
+
+// sealedSwitches checks the type switches in one function body
+func sealedSwitches(p *pass, fn string, body *ast.BlockStmt) []Finding {
+	var findings []Finding
+	walkFunc(body, func(n ast.Node, _ []ast.Node) {
+		if ts, ok := n.(*ast.TypeSwitchStmt); ok {
+			if f, ok := checkTypeSwitch(p, fn, ts); ok {
+				findings = append(findings, f)
+			}
+		}
+	})
+	return findings
+}
+
+// checkTypeSwitch checks one type switch, reporting whether it is missing
+// cases
+func checkTypeSwitch(p *pass, fn string, ts *ast.TypeSwitchStmt) (Finding, bool) {
+	var assert *ast.TypeAssertExpr
+	switch s := ts.Assign.(type) {
+	case *ast.AssignStmt:
+		assert, _ = s.Rhs[0].(*ast.TypeAssertExpr)
+	case *ast.ExprStmt:
+		assert, _ = s.X.(*ast.TypeAssertExpr)
+	}
+	if assert == nil {
+		return Finding{}, false
+	}
+	switched := p.info.TypeOf(assert.X)
+	if switched == nil {
+		return Finding{}, false
+	}
+	iface, ok := switched.Underlying().(*types.Interface)
+	if !ok || !Sealed(iface) {
+		return Finding{}, false
+	}
+
+	var cases []types.Type
+	hasDefault := false
+	for _, stmt := range ts.Body.List {
+		clause := stmt.(*ast.CaseClause)
+		if clause.List == nil {
+			hasDefault = true
+		}
+		for _, e := range clause.List {
+			if t := p.info.TypeOf(e); t != nil && !p.info.Types[e].IsNil() {
+				cases = append(cases, t)
+			}
+		}
+	}
+
+	// The implementations are in the package the interface is sealed in,
+	// which is usually not the one with the switch. Unexported ones cannot
+	// be named in a case there, so only an interface case can cover them.
+	sealed := sealedIn(iface)
+	if sealed == nil {
+		return Finding{}, false
+	}
+	qualify := func(other *types.Package) string {
+		if other == p.pkg {
+			return ""
+		}
+		return other.Name()
+	}
+	var missing []string
+	for _, impl := range Implementations(sealed, iface) {
+		if sealed != p.pkg && !impl.Obj().Exported() {
+			continue
+		}
+		if !covered(impl, cases) {
+			missing = append(missing, types.TypeString(impl, qualify))
+		}
+	}
+	if len(missing) == 0 {
+		return Finding{}, false
+	}
+	sort.Strings(missing)
+
+	name := types.TypeString(switched, qualify)
+	message := fmt.Sprintf("type switch over sealed interface %s has no case for %s", name, strings.Join(missing, ", "))
+	if hasDefault {
+		message += "; the default case silently handles what is missing"
+	}
+	return Finding{
+		Check:   "sealed-switch",
+		Pos:     p.fset.Position(ts.Pos()),
+		Func:    fn,
+		Message: message,
+		Fix: fmt.Sprintf("Add a case for %s. Leave out the default case, or make it panic, so that adding a type to %s is reported here.",
+			strings.Join(missing, ", "), name),
+	}, true
+}
+
+// covered reports whether one of the cases matches values of type impl: a
+// case for the type itself, for a pointer to it, or for an interface it
+// implements
+func covered(impl *types.Named, cases []types.Type) bool {
+	for _, c := range cases {
+		if types.Identical(c, impl) {
+			return true
+		}
+		if ptr, ok := c.(*types.Pointer); ok && types.Identical(ptr.Elem(), impl) {
+			return true
+		}
+		if caseIface, ok := c.Underlying().(*types.Interface); ok {
+			if types.Implements(impl, caseIface) || types.Implements(types.NewPointer(impl), caseIface) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestSealed(t *testing.T) {
+	pkg := types.NewPackage("example.com/shapes", "shapes")
+	method := func(name string) *types.Func {
+		return types.NewFunc(token.NoPos, pkg, name, types.NewSignatureType(nil, nil, nil, nil, nil, false))
+	}
+	shape := types.NewInterfaceType([]*types.Func{method("isShape")}, nil).Complete()
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Shape", nil), shape, nil)
+	polygon := types.NewInterfaceType([]*types.Func{method("Corners")}, []types.Type{named}).Complete()
+	stringer := types.NewInterfaceType([]*types.Func{method("String")}, nil).Complete()
+
+	for _, tc := range []struct {
+		name   string
+		iface  *types.Interface
+		sealed bool
+	}{
+		{"unexported method", shape, true},
+		{"embedded sealed interface", polygon, true},
+		{"exported methods only", stringer, false},
+		{"empty interface", types.NewInterfaceType(nil, nil).Complete(), false},
+	} {
+		if got := Sealed(tc.iface); got != tc.sealed {
+			t.Errorf("%s: Sealed = %t, want %t", tc.name, got, tc.sealed)
+		}
+		if got := sealedIn(tc.iface); (got == pkg) != tc.sealed {
+			t.Errorf("%s: sealedIn = %v, want %v", tc.name, got, tc.sealed)
+		}
+	}
+}
+
+// shapes is a sealed Shape with two variants, Square and Triangle, that
+// are also a Polygon, which is sealed only by embedding Shape, and one,
+// Circle, that is not
+const shapes = `
+type Shape interface{ isShape() }
+
+type Polygon interface {
+	Shape
+	Corners() int
+}
+
+type Square struct{}
+type Triangle struct{}
+type Circle struct{}
+
+func (Square) isShape()       {}
+func (Triangle) isShape()     {}
+func (Circle) isShape()       {}
+func (Square) Corners() int   { return 4 }
+func (Triangle) Corners() int { return 3 }
+`
+
+func TestSealedSwitch(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		code string
+		want []string
+	}{
+		{
+			"switch over the embedding interface",
+			`func corners(p Polygon) {
+	switch p.(type) {
+	case Square:
+	}
+}`,
+			[]string{"type switch over sealed interface Polygon has no case for Triangle"},
+		},
+		{
+			"embedding interface covered",
+			`func corners(p Polygon) {
+	switch p.(type) {
+	case Square, *Triangle:
+	}
+}`,
+			nil,
+		},
+		{
+			"embedding interface as a case",
+			`func area(s Shape) {
+	switch s.(type) {
+	case Polygon:
+	}
+}`,
+			[]string{"type switch over sealed interface Shape has no case for Circle"},
+		},
+		{
+			"embedded interface covered through the embedding one",
+			`func area(s Shape) {
+	switch s.(type) {
+	case Polygon:
+	case Circle:
+	}
+}`,
+			nil,
+		},
+		{
+			"default case",
+			`func area(s Shape) {
+	switch s.(type) {
+	case Circle:
+	default:
+	}
+}`,
+			[]string{"type switch over sealed interface Shape has no case for Square, Triangle; the default case silently handles what is missing"},
+		},
+	} {
+		findings, err := Snippet(shapes + "\n" + tc.code + "\n\nfunc main() {}\n")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var got []string
+		for _, f := range findings {
+			if f.Check == "sealed-switch" {
+				got = append(got, f.Message)
+			}
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: findings %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
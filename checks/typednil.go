@@ -0,0 +1,237 @@
+package checks
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// typedNil reports functions that return a pointer which may be nil as an
+// interface result, such as a nil *CompFileHandler returned as a
+// CompCloser. The caller gets an interface holding a nil pointer, which is
+// not equal to nil, so a check like err != nil goes the wrong way.
+//
+// A pointer may be nil when it is converted from nil, as in (*T)(nil), or
+// when it is a local variable that was declared without a value or set to
+// nil, and not unconditionally set to something else before the return.
+func typedNil(p *pass) []Finding {
+	var findings []Finding
+	for _, file := range p.files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			if obj := p.info.Defs[fn.Name]; obj != nil {
+				findings = append(findings, typedNilReturns(p, fn.Name.Name, obj.Type(), fn.Body)...)
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if lit, ok := n.(*ast.FuncLit); ok {
+					findings = append(findings, typedNilReturns(p, "a function literal in "+fn.Name.Name, p.info.TypeOf(lit), lit.Body)...)
+				}
+				return true
+			})
+		}
+	}
+	return findings
+}
+
+// typedNilReturns checks the return statements of one function
+func typedNilReturns(p *pass, name string, typ types.Type, body *ast.BlockStmt) []Finding {
+	sig, ok := typ.(*types.Signature)
+	if !ok {
+		return nil
+	}
+	results := sig.Results()
+	var interfaces []int
+	for i := 0; i < results.Len(); i++ {
+		// IsInterface is also true of a type parameter, whose constraint is
+		// an interface, but a T result holds the type argument itself: a
+		// nil *X returned as T is just a nil pointer
+		t := results.At(i).Type()
+		if _, param := t.(*types.TypeParam); types.IsInterface(t) && !param {
+			interfaces = append(interfaces, i)
+		}
+	}
+	if len(interfaces) == 0 {
+		return nil
+	}
+
+	vars := nilAssignments(p, body)
+	var findings []Finding
+	walkFunc(body, func(n ast.Node, _ []ast.Node) {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != results.Len() {
+			return
+		}
+		for _, i := range interfaces {
+			expr := unparen(ret.Results[i])
+			typ := p.info.TypeOf(expr)
+			if typ == nil {
+				continue
+			}
+			if _, ok := typ.Underlying().(*types.Pointer); !ok {
+				continue
+			}
+			qualify := types.RelativeTo(p.pkg)
+			iface := types.TypeString(results.At(i).Type(), qualify)
+			ptr := types.TypeString(typ, qualify)
+
+			var message string
+			switch {
+			case isNilConversion(p, expr):
+				message = fmt.Sprintf("returns a nil %s as %s: callers get a non-nil %s, because it holds a type", ptr, iface, iface)
+			case isIdent(expr) && vars.mayBeNil(p.info.ObjectOf(expr.(*ast.Ident)), ret.Pos()):
+				v := expr.(*ast.Ident).Name
+				message = fmt.Sprintf("returns %s, a %s that may be nil, as %s: when %s is nil, callers get a non-nil %s", v, ptr, iface, v, iface)
+			default:
+				continue
+			}
+			findings = append(findings, Finding{
+				Check:   "typed-nil",
+				Pos:     p.fset.Position(ret.Pos()),
+				Func:    name,
+				Message: message,
+				Fix:     fmt.Sprintf("Return a literal nil for the %s result when there is nothing to return, so callers can compare it with nil.", iface),
+			})
+		}
+	})
+	return findings
+}
+
+// isNilConversion reports whether expr converts nil to a type, as (*T)(nil)
+func isNilConversion(p *pass, expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	return ok && len(call.Args) == 1 && p.info.Types[call.Fun].IsType() && p.info.Types[call.Args[0]].IsNil()
+}
+
+// unparen removes the parentheses around an expression
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		paren, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = paren.X
+	}
+}
+
+func isIdent(expr ast.Expr) bool {
+	_, ok := expr.(*ast.Ident)
+	return ok
+}
+
+// assignments records, for each local variable, where it may become nil and
+// where it is certainly set to something else
+type assignments struct {
+	nils   map[types.Object][]token.Pos
+	clears map[types.Object][]token.Pos
+}
+
+// mayBeNil reports whether v may be nil at pos: it became nil before pos
+// and was not certainly set to something else between the two
+func (a assignments) mayBeNil(v types.Object, pos token.Pos) bool {
+	for _, n := range a.nils[v] {
+		if n >= pos {
+			continue
+		}
+		cleared := false
+		for _, c := range a.clears[v] {
+			if c > n && c < pos {
+				cleared = true
+			}
+		}
+		if !cleared {
+			return true
+		}
+	}
+	return false
+}
+
+// nilAssignments finds the declarations and assignments of the function's
+// local pointer variables. An assignment only clears a variable when it is
+// made directly in the block that declares it, so it happens whatever
+// branches are taken.
+func nilAssignments(p *pass, body *ast.BlockStmt) assignments {
+	a := assignments{nils: make(map[types.Object][]token.Pos), clears: make(map[types.Object][]token.Pos)}
+	declared := make(map[types.Object]*ast.BlockStmt)
+
+	isPointer := func(obj types.Object) bool {
+		if obj == nil {
+			return false
+		}
+		_, ok := obj.Type().Underlying().(*types.Pointer)
+		return ok
+	}
+	isNil := func(e ast.Expr) bool {
+		e = unparen(e)
+		return p.info.Types[e].IsNil() || isNilConversion(p, e)
+	}
+
+	walkFunc(body, func(n ast.Node, stack []ast.Node) {
+		var block *ast.BlockStmt
+		if len(stack) > 0 {
+			block, _ = stack[len(stack)-1].(*ast.BlockStmt)
+		}
+		switch s := n.(type) {
+		case *ast.ValueSpec:
+			// The block of a var declaration is above its DeclStmt and GenDecl
+			if len(stack) >= 3 {
+				block, _ = stack[len(stack)-3].(*ast.BlockStmt)
+			}
+			for i, ident := range s.Names {
+				obj := p.info.Defs[ident]
+				if !isPointer(obj) {
+					continue
+				}
+				declared[obj] = block
+				if len(s.Values) == 0 || (len(s.Values) == len(s.Names) && isNil(s.Values[i])) {
+					a.nils[obj] = append(a.nils[obj], ident.Pos())
+				}
+			}
+		case *ast.AssignStmt:
+			if len(s.Lhs) != len(s.Rhs) {
+				return
+			}
+			for i, lhs := range s.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				obj := p.info.ObjectOf(ident)
+				if !isPointer(obj) {
+					continue
+				}
+				if s.Tok == token.DEFINE && p.info.Defs[ident] != nil {
+					declared[obj] = block
+				}
+				switch {
+				case isNil(s.Rhs[i]):
+					a.nils[obj] = append(a.nils[obj], s.Pos())
+				case block != nil && declared[obj] == block:
+					a.clears[obj] = append(a.clears[obj], s.Pos())
+				}
+			}
+		}
+	})
+	return a
+}
+
+// walkFunc calls visit for every node of a function body with the nodes
+// above it, leaving out function literals, which are checked on their own
+func walkFunc(body *ast.BlockStmt, visit func(n ast.Node, stack []ast.Node)) {
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		visit(n, stack)
+		stack = append(stack, n)
+		return true
+	})
+}
@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypedNil(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		code string
+		want []string
+	}{
+		{
+			"nil pointer as an error",
+			`type myErr struct{}
+
+func (*myErr) Error() string { return "" }
+
+func check() error {
+	var e *myErr
+	return e
+}`,
+			[]string{"check"},
+		},
+		{
+			"nil pointer as a type parameter",
+			// *int is assignable to T, since it is the only type in T's type
+			// set; the T result is the pointer, not an interface holding it
+			`func zero[T any]() T {
+	var v T
+	return v
+}
+
+func cell[T *int]() T {
+	var p *int
+	return p
+}
+
+func none[T *int]() T {
+	return (*int)(nil)
+}`,
+			nil,
+		},
+		{
+			"nil pointer as an interface in a generic function",
+			`type myErr struct{}
+
+func (*myErr) Error() string { return "" }
+
+func find[T any](items []T) (T, error) {
+	var zero T
+	var e *myErr
+	return zero, e
+}`,
+			[]string{"find"},
+		},
+	} {
+		findings, err := Snippet(tc.code + "\n\nfunc main() {}\n")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var got []string
+		for _, f := range findings {
+			if f.Check == "typed-nil" {
+				got = append(got, f.Func)
+			}
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: typed nil returns in %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
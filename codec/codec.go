@@ -0,0 +1,281 @@
+// Package codec encodes and decodes interface values as JSON. A Registry
+// knows the concrete types that may be stored in one interface, such as
+// BasicShape, each under a name. The name is written to a discriminator
+// field next to the value's own fields, as in
+//
+//	{"type":"circle","radius":3}
+//
+// and decoding reads it back to pick the concrete type. Names that are not
+// registered are rejected, so a file can never produce a value of a type
+// the program does not expect.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Field is the name of the discriminator field
+const Field = "type"
+
+// Registry encodes and decodes values of the interface type T
+type Registry[T any] struct {
+	family string // what a T is called in errors, such as "shape"
+	types  map[string]reflect.Type
+	names  map[reflect.Type]string
+}
+
+// NewRegistry returns an empty registry for the interface type T. Family
+// names the values in error messages, such as "shape" or "animal".
+func NewRegistry[T any](family string) *Registry[T] {
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() != reflect.Interface {
+		panic("codec: NewRegistry needs an interface type, not " + reflect.TypeOf((*T)(nil)).Elem().String())
+	}
+	return &Registry[T]{
+		family: family,
+		types:  make(map[string]reflect.Type),
+		names:  make(map[reflect.Type]string),
+	}
+}
+
+// Register adds the concrete type of example under name. Values of that
+// type are encoded with the name, and the name decodes to that type: to a
+// pointer when example is a pointer, to a plain value otherwise. The type
+// must encode as a JSON object without a field of the discriminator's name.
+func (r *Registry[T]) Register(name string, example T) error {
+	t := reflect.TypeOf(example)
+	switch {
+	case name == "":
+		return errors.New("codec: empty type name")
+	case t == nil:
+		return fmt.Errorf("codec: cannot register a nil %s as %q", r.family, name)
+	case r.types[name] != nil:
+		return fmt.Errorf("codec: %s type %q is already registered for %s", r.family, name, r.types[name])
+	case r.names[t] != "":
+		return fmt.Errorf("codec: %s is already registered as %q", t, r.names[t])
+	}
+	base := t
+	if base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	if base.Kind() != reflect.Struct {
+		return fmt.Errorf("codec: %s does not encode as a JSON object", t)
+	}
+	if fieldNamed(base, Field) {
+		return fmt.Errorf("codec: %s has a field encoded as %q, the discriminator", t, Field)
+	}
+	r.types[name] = t
+	r.names[t] = name
+	return nil
+}
+
+// MustRegister is like Register but panics on an error. It is meant for
+// registries filled in when the program starts.
+func (r *Registry[T]) MustRegister(name string, example T) *Registry[T] {
+	if err := r.Register(name, example); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Names returns the registered type names in sorted order
+func (r *Registry[T]) Names() []string {
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Marshal encodes v as a JSON object whose first field is the
+// discriminator. A nil pointer has no fields to write and is an error, as
+// is a type whose MarshalJSON method does not write an object.
+func (r *Registry[T]) Marshal(v T) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("codec: cannot encode a nil %s", r.family)
+	}
+	name, ok := r.names[t]
+	if !ok {
+		return nil, fmt.Errorf("codec: %s is not a registered %s type (registered: %s)", t, r.family, strings.Join(r.Names(), ", "))
+	}
+	if t.Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, fmt.Errorf("codec: cannot encode a nil %s as %s %q", t, r.family, name)
+	}
+	fields, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("codec: encoding %s %q: %w", r.family, name, err)
+	}
+	if len(fields) < 2 || fields[0] != '{' {
+		return nil, fmt.Errorf("codec: %s %q encodes as %s, not as a JSON object", r.family, name, fields)
+	}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	key, _ := json.Marshal(Field)
+	value, _ := json.Marshal(name)
+	b.Write(key)
+	b.WriteByte(':')
+	b.Write(value)
+	if rest := bytes.TrimSpace(fields[1:]); len(rest) > 1 {
+		b.WriteByte(',')
+	}
+	b.Write(fields[1:])
+	return b.Bytes(), nil
+}
+
+// MarshalSlice encodes vs as a JSON array of objects, as Marshal does
+func (r *Registry[T]) MarshalSlice(vs []T) ([]byte, error) {
+	items := make([]json.RawMessage, len(vs))
+	for i, v := range vs {
+		data, err := r.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", r.family, i, err)
+		}
+		items[i] = data
+	}
+	return json.Marshal(items)
+}
+
+// MarshalIndent is like MarshalSlice but indents the array for people to
+// read
+func (r *Registry[T]) MarshalIndent(vs []T, prefix, indent string) ([]byte, error) {
+	data, err := r.MarshalSlice(vs)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, prefix, indent); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Unmarshal decodes one JSON object into the type its discriminator names.
+// A missing or unknown discriminator, and a field the type does not have,
+// are errors.
+func (r *Registry[T]) Unmarshal(data []byte) (T, error) {
+	var zero T
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return zero, fmt.Errorf("codec: a %s must be a JSON object: %w", r.family, err)
+	}
+	if fields == nil {
+		return zero, fmt.Errorf("codec: a %s must be a JSON object, not null", r.family)
+	}
+	raw, ok := fields[Field]
+	if !ok {
+		return zero, &UnknownTypeError{Family: r.family, Missing: true, Known: r.Names()}
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return zero, fmt.Errorf("codec: the %q field of a %s must be a string, not %s", Field, r.family, raw)
+	}
+	t, ok := r.types[name]
+	if !ok {
+		return zero, &UnknownTypeError{Family: r.family, Name: name, Known: r.Names()}
+	}
+
+	delete(fields, Field)
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return zero, err
+	}
+	base := t
+	if t.Kind() == reflect.Pointer {
+		base = t.Elem()
+	}
+	p := reflect.New(base)
+	dec := json.NewDecoder(bytes.NewReader(rest))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p.Interface()); err != nil {
+		return zero, fmt.Errorf("codec: decoding %s %q: %w", r.family, name, err)
+	}
+	decoded := p.Elem()
+	if t.Kind() == reflect.Pointer {
+		decoded = p
+	}
+	v := decoded.Interface().(T)
+	if val, ok := interface{}(v).(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return zero, fmt.Errorf("codec: invalid %s %q: %w", r.family, name, err)
+		}
+	}
+	return v, nil
+}
+
+// Validator is a type that checks its own fields. Unmarshal calls Validate
+// on every value it decodes, so a value that decodes but makes no sense,
+// such as a polygon with one side, is rejected like unknown input.
+type Validator interface {
+	Validate() error
+}
+
+// UnmarshalSlice decodes a JSON array of objects, as Unmarshal does. An
+// error names the position of the element that could not be decoded.
+func (r *Registry[T]) UnmarshalSlice(data []byte) ([]T, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("codec: expected a JSON array of %ss: %w", r.family, err)
+	}
+	vs := make([]T, len(items))
+	for i, item := range items {
+		v, err := r.Unmarshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", r.family, i, err)
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+// UnknownTypeError is returned when the discriminator of an object is
+// missing or names a type that is not registered
+type UnknownTypeError struct {
+	Family  string
+	Name    string   // the unknown name
+	Missing bool     // the object has no discriminator at all
+	Known   []string // the registered names
+}
+
+func (e *UnknownTypeError) Error() string {
+	known := strings.Join(e.Known, ", ")
+	if e.Missing {
+		return fmt.Sprintf("codec: the %s has no %q field; set it to one of: %s", e.Family, Field, known)
+	}
+	return fmt.Sprintf("codec: unknown %s type %q; it must be one of: %s", e.Family, e.Name, known)
+}
+
+// fieldNamed reports whether the struct type t has a field that encodes as
+// name, ignoring case as encoding/json does when decoding
+func fieldNamed(t reflect.Type, name string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		key := strings.Split(tag, ",")[0]
+		if key == "" {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				if fieldNamed(f.Type, name) {
+					return true
+				}
+				continue
+			}
+			key = f.Name
+		}
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
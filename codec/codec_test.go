@@ -0,0 +1,176 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type shape interface{ Area() float64 }
+
+type square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *square) Area() float64 { return s.Side * s.Side }
+
+// point encodes as an array, which cannot take a discriminator
+type point struct{ X, Y float64 }
+
+func (p point) Area() float64                { return 0 }
+func (p point) MarshalJSON() ([]byte, error) { return json.Marshal([]float64{p.X, p.Y}) }
+
+// empty encodes as an object without fields
+type empty struct{}
+
+func (empty) Area() float64 { return 0 }
+
+func TestMarshal(t *testing.T) {
+	r := NewRegistry[shape]("shape").
+		MustRegister("square", &square{}).
+		MustRegister("point", point{}).
+		MustRegister("empty", empty{})
+
+	for _, tc := range []struct {
+		name string
+		v    shape
+		want string
+		err  string
+	}{
+		{"pointer", &square{Side: 2}, `{"type":"square","side":2}`, ""},
+		{"no fields", empty{}, `{"type":"empty"}`, ""},
+		{"nil pointer", (*square)(nil), "", "nil *codec.square"},
+		{"nil interface", nil, "", "nil shape"},
+		{"not an object", point{X: 1, Y: 2}, "", "not as a JSON object"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := r.Marshal(tc.v)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("Marshal = %s, %v, want an error containing %q", data, err, tc.err)
+				}
+				return
+			}
+			if err != nil || string(data) != tc.want {
+				t.Fatalf("Marshal = %s, %v, want %s", data, err, tc.want)
+			}
+			if !json.Valid(data) {
+				t.Fatalf("Marshal wrote invalid JSON: %s", data)
+			}
+		})
+	}
+}
+
+// checked rejects negative sides through Validator
+type checked struct {
+	Side float64 `json:"side"`
+}
+
+func (c checked) Area() float64 { return c.Side * c.Side }
+
+func (c checked) Validate() error {
+	if c.Side < 0 {
+		return errors.New("negative side")
+	}
+	return nil
+}
+
+func TestUnmarshalValidates(t *testing.T) {
+	r := NewRegistry[shape]("shape").MustRegister("checked", checked{}).MustRegister("square", &square{})
+	if v, err := r.Unmarshal([]byte(`{"type":"checked","side":2}`)); err != nil || v != (checked{Side: 2}) {
+		t.Errorf("Unmarshal = %v, %v, want a checked with side 2", v, err)
+	}
+	if _, err := r.Unmarshal([]byte(`{"type":"checked","side":-2}`)); err == nil || !strings.Contains(err.Error(), "negative side") {
+		t.Errorf("Unmarshal of a negative side: err = %v, want the Validate error", err)
+	}
+	// Types registered as pointers decode to pointers
+	if v, err := r.Unmarshal([]byte(`{"type":"square","side":3}`)); err != nil || v.Area() != 9 {
+		t.Errorf("Unmarshal = %v, %v, want a *square with side 3", v, err)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	r := NewRegistry[shape]("shape").MustRegister("square", &square{}).MustRegister("empty", empty{})
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"unknown type", `{"type":"hexagon","side":2}`, `unknown shape type "hexagon"; it must be one of: empty, square`},
+		{"missing type", `{"side":2}`, `the shape has no "type" field; set it to one of: empty, square`},
+		{"type not a string", `{"type":3}`, `the "type" field of a shape must be a string, not 3`},
+		{"unknown field", `{"type":"square","side":2,"colour":"red"}`, `decoding shape "square": json: unknown field "colour"`},
+		{"wrong field type", `{"type":"square","side":"two"}`, `decoding shape "square"`},
+		{"not an object", `[1,2]`, "a shape must be a JSON object"},
+		{"null", `null`, "a shape must be a JSON object, not null"},
+		{"invalid JSON", `{"type":`, "a shape must be a JSON object"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := r.Unmarshal([]byte(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("Unmarshal = %v, %v, want an error containing %q", v, err, tc.err)
+			}
+			if v != nil {
+				t.Errorf("Unmarshal returned %v with the error, want nil", v)
+			}
+		})
+	}
+}
+
+func TestUnknownTypeError(t *testing.T) {
+	r := NewRegistry[shape]("shape").MustRegister("square", &square{}).MustRegister("empty", empty{})
+
+	for _, tc := range []struct {
+		input string
+		want  UnknownTypeError
+	}{
+		{`{"type":"hexagon"}`, UnknownTypeError{Family: "shape", Name: "hexagon", Known: []string{"empty", "square"}}},
+		{`{"side":2}`, UnknownTypeError{Family: "shape", Missing: true, Known: []string{"empty", "square"}}},
+	} {
+		_, err := r.Unmarshal([]byte(tc.input))
+		var unknown *UnknownTypeError
+		if !errors.As(err, &unknown) {
+			t.Errorf("Unmarshal(%s): err = %v, want an *UnknownTypeError", tc.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(*unknown, tc.want) {
+			t.Errorf("Unmarshal(%s): error %+v, want %+v", tc.input, *unknown, tc.want)
+		}
+	}
+}
+
+func TestSliceRoundTrip(t *testing.T) {
+	r := NewRegistry[shape]("shape").MustRegister("square", &square{}).MustRegister("empty", empty{})
+	shapes := []shape{&square{Side: 2}, empty{}, &square{Side: 0.5}}
+
+	data, err := r.MarshalSlice(shapes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"type":"square","side":2},{"type":"empty"},{"type":"square","side":0.5}]`; string(data) != want {
+		t.Errorf("MarshalSlice = %s, want %s", data, want)
+	}
+	decoded, err := r.UnmarshalSlice(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, shapes) {
+		t.Errorf("UnmarshalSlice = %#v, want %#v", decoded, shapes)
+	}
+
+	// Errors name the element that failed
+	if _, err := r.MarshalSlice([]shape{empty{}, (*square)(nil)}); err == nil || !strings.HasPrefix(err.Error(), "shape 1: ") {
+		t.Errorf("MarshalSlice with a nil second element: err = %v, want it to name shape 1", err)
+	}
+	_, err = r.UnmarshalSlice([]byte(`[{"type":"empty"},{"type":"square","side":1},{"type":"circle"}]`))
+	var unknown *UnknownTypeError
+	if err == nil || !strings.HasPrefix(err.Error(), "shape 2: ") || !errors.As(err, &unknown) || unknown.Name != "circle" {
+		t.Errorf("UnmarshalSlice with an unknown third element: err = %v, want it to name shape 2 and wrap an *UnknownTypeError", err)
+	}
+	if _, err := r.UnmarshalSlice([]byte(`{"type":"empty"}`)); err == nil || !strings.Contains(err.Error(), "expected a JSON array of shapes") {
+		t.Errorf("UnmarshalSlice of an object: err = %v, want an error asking for an array", err)
+	}
+}
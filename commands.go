@@ -0,0 +1,554 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"go-interface-enum-explorer/bench"
+	"go-interface-enum-explorer/checks"
+	"go-interface-enum-explorer/compare"
+	"go-interface-enum-explorer/escape"
+	"go-interface-enum-explorer/examples"
+	"go-interface-enum-explorer/explain"
+	"go-interface-enum-explorer/export"
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/inspect"
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/pipeline"
+	"go-interface-enum-explorer/sandbox"
+	"go-interface-enum-explorer/snippet"
+	"go-interface-enum-explorer/svg"
+	"go-interface-enum-explorer/utils"
+)
+
+// runCommand executes a non-interactive command given on the command line
+// and returns the process exit code
+func runCommand(args []string) int {
+	switch args[0] {
+	case "export":
+		return exportCommand(args[1:])
+	case "path":
+		return pathCommand(args[1:])
+	case "search":
+		return searchCommand(args[1:])
+	case "glossary":
+		return glossaryCommand(args[1:])
+	case "i18n":
+		return i18nCommand(args[1:])
+	case "compare":
+		return compareCommand(args[1:])
+	case "run":
+		return runFileCommand(args[1:])
+	case "explain":
+		return explainCommand(args[1:])
+	case "bench":
+		return benchCommand(args[1:])
+	case "escape":
+		return escapeCommand(args[1:])
+	case "inspect":
+		return inspectCommand(args[1:])
+	case "check":
+		return checkCommand(args[1:])
+	case "draw":
+		return drawCommand(args[1:])
+	case "conform":
+		return conformCommand(args[1:])
+	case "help":
+		printUsage()
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T("command.unknown", args[0])+"\n")
+		printUsage()
+		return 2
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, i18n.T("usage.commands"))
+	fmt.Fprintln(os.Stderr, "\n"+i18n.T("usage.flags"))
+	flag.PrintDefaults()
+}
+
+// exportFormats maps each export format to its default output path and the
+// function that writes it
+var exportFormats = map[string]struct {
+	defaultOut string
+	write      func(string, []*lessons.Lesson) error
+}{
+	"html":     {"site", export.HTML},
+	"epub":     {"go-interface-enum-explorer.epub", export.EPUB},
+	"markdown": {"go-interface-enum-explorer.md", export.Markdown},
+	"notebook": {"notebooks", export.Notebooks},
+}
+
+func exportCommand(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		args = append([]string{prefs.OutputFormat}, args...)
+	}
+
+	name := args[0]
+	format, ok := exportFormats[name]
+	if !ok {
+		fmt.Fprintln(os.Stderr, i18n.T("export.unknown_format", name))
+		return 2
+	}
+
+	fs := flag.NewFlagSet("export "+name, flag.ContinueOnError)
+	out := fs.String("out", format.defaultOut, "output path")
+	only := fs.String("lesson", "", "export only the lesson with this ID")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	// Lessons are registered in tutorial order: interfaces first, then enums
+	selected := lessons.All()
+	if *only != "" {
+		lesson := lessons.Lookup(*only)
+		if lesson == nil {
+			fmt.Fprintln(os.Stderr, i18n.T("export.no_lesson", *only))
+			return 2
+		}
+		selected = []*lessons.Lesson{lesson}
+	}
+
+	if err := format.write(*out, selected); err != nil {
+		fmt.Fprintf(os.Stderr, "export %s: %v\n", name, err)
+		return 1
+	}
+	if len(selected) == 1 {
+		fmt.Println(i18n.T("export.done_one", *out))
+	} else {
+		fmt.Println(i18n.T("export.done", len(selected), *out))
+	}
+	return 0
+}
+
+func pathCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T("path.usage"))
+		return 2
+	}
+	path, err := lessons.PathTo(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "path: %v\n", err)
+		return 1
+	}
+	printPath(path)
+	return 0
+}
+
+func searchCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("search.usage"))
+		return 2
+	}
+	query := strings.Join(args, " ")
+	results := lessonIndex().Search(query, 10)
+	if len(results) == 0 {
+		fmt.Println(i18n.T("search.no_match", query))
+		return 1
+	}
+	printSearchResults(results)
+	return 0
+}
+
+func glossaryCommand(args []string) int {
+	if len(args) == 0 {
+		for _, term := range lessons.Glossary {
+			fmt.Printf("%-24s %s\n", term.Name, term.Definition)
+		}
+		return 0
+	}
+	term := lessons.LookupTerm(strings.Join(args, " "))
+	if term == nil {
+		fmt.Fprintln(os.Stderr, i18n.T("glossary.no_term", strings.Join(args, " ")))
+		return 1
+	}
+	printTerm(term)
+	return 0
+}
+
+// i18nCommand writes a catalog of the messages a language does not
+// translate yet, with their English text, for translators to fill in
+func i18nCommand(args []string) int {
+	if len(args) < 2 || args[0] != "extract" {
+		fmt.Fprintln(os.Stderr, i18n.T("i18n.usage"))
+		return 2
+	}
+	lang := args[1]
+
+	fs := flag.NewFlagSet("i18n extract", flag.ContinueOnError)
+	out := fs.String("out", "", "write the catalog to FILE instead of standard output")
+	if err := fs.Parse(args[2:]); err != nil {
+		return 2
+	}
+
+	messages := i18n.English()
+	lessonMessages := lessons.Messages()
+	for id, text := range lessonMessages {
+		messages[id] = text
+	}
+	missing := i18n.Untranslated(lang, messages)
+	untranslated := len(missing)
+	// Lesson text changes over time, so its translations record which
+	// English they were made from
+	for id, text := range lessonMessages {
+		if _, ok := missing[id]; ok {
+			missing[id+".source"] = i18n.Fingerprint(text)
+		}
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "i18n: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(missing); err != nil {
+		fmt.Fprintf(os.Stderr, "i18n: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("i18n.untranslated", untranslated, len(messages), lang))
+	return 0
+}
+
+func compareCommand(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, i18n.T("compare.usage"))
+		return 2
+	}
+	var pair [2]*lessons.Lesson
+	for i, name := range args {
+		if pair[i] = lessons.Lookup(name); pair[i] == nil {
+			fmt.Fprintln(os.Stderr, i18n.T("compare.no_lesson", name))
+			return 2
+		}
+	}
+	for _, line := range comparisonLines(compare.Lessons(pair[0], pair[1])) {
+		fmt.Println(line)
+	}
+	return 0
+}
+
+// runFileCommand compiles and runs a Go file in the sandbox. Code without a
+// package clause, such as a lesson's code, is completed first.
+func runFileCommand(args []string) int {
+	defaults := sandbox.DefaultLimits()
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", defaults.WallTime, "wall-clock time limit")
+	cpu := fs.Duration("cpu", defaults.CPUTime, "CPU time limit")
+	memory := fs.Uint64("memory", defaults.Memory>>20, "memory limit in MB")
+	output := fs.Int64("output", defaults.Output>>10, "output limit in KB")
+	network := fs.Bool("network", false, "allow network access")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T("run.usage"))
+		return 2
+	}
+
+	code, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run: %v\n", err)
+		return 1
+	}
+	limits := sandbox.Limits{WallTime: *timeout, CPUTime: *cpu, Memory: *memory << 20, Output: *output << 10, Network: *network}
+	result, err := sandbox.Run([]byte(snippet.Program(string(code))), limits)
+	if compileErr, ok := err.(*sandbox.CompileError); ok {
+		fmt.Fprintln(os.Stderr, i18n.T("run.compile_failed"))
+		fmt.Fprintln(os.Stderr, utils.Colorize(utils.ColorRed, compileErr.Output))
+		explained, _ := explain.Explain(compileErr.Output)
+		printExplanations(explained)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run: %v\n", err)
+		return 1
+	}
+	printRunResult(result)
+	if result.Success() {
+		return 0
+	}
+	if result.ExitCode > 0 {
+		return result.ExitCode
+	}
+	return 1
+}
+
+// printRunResult shows what a sandboxed program printed and how it ended
+func printRunResult(result *sandbox.Result) {
+	fmt.Print(result.Stdout)
+	if result.Stdout != "" && !strings.HasSuffix(result.Stdout, "\n") {
+		fmt.Println()
+	}
+	if result.Stderr != "" {
+		fmt.Fprint(os.Stderr, utils.Colorize(utils.ColorRed, strings.TrimRight(result.Stderr, "\n"))+"\n")
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, utils.Colorize(utils.ColorYellow, i18n.T("run.warning", warning)))
+	}
+	fmt.Fprintln(os.Stderr, utils.Colorize(utils.ColorGray, i18n.T("run.summary", result.Summary())))
+}
+
+// explainCommand explains the interface and enum mistakes in the output of
+// go build or go vet, read from a file or pasted on standard input
+func explainCommand(args []string) int {
+	var input []byte
+	var err error
+	switch len(args) {
+	case 0:
+		if info, statErr := os.Stdin.Stat(); statErr == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprintln(os.Stderr, i18n.T("explain.paste"))
+		}
+		input, err = io.ReadAll(os.Stdin)
+	case 1:
+		input, err = os.ReadFile(args[0])
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T("explain.usage"))
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "explain: %v\n", err)
+		return 1
+	}
+
+	explained, unknown := explain.Explain(string(input))
+	if len(explained) == 0 {
+		fmt.Println(i18n.T("explain.none"))
+		return 1
+	}
+	printExplanations(explained)
+	if len(unknown) > 0 {
+		message := i18n.T("explain.unknown", len(unknown))
+		if len(unknown) == 1 {
+			message = i18n.T("explain.unknown_one")
+		}
+		fmt.Println(utils.Colorize(utils.ColorGray, message))
+	}
+	return 0
+}
+
+// printExplanations shows each recognized error with what it means, how to
+// fix it and where the tutorial covers it
+func printExplanations(explained []explain.Explanation) {
+	for _, e := range explained {
+		message := strings.ReplaceAll(e.Diagnostic.Message, "\n", "\n    ")
+		if pos := e.Diagnostic.Position(); pos != "" {
+			message = pos + ": " + message
+		}
+		fmt.Println()
+		fmt.Println(utils.Colorize(utils.ColorRed, message))
+		fmt.Println("  " + i18n.T("explain.meaning", e.Meaning))
+		fmt.Println("  " + i18n.T("explain.fix", e.Fix))
+		if lesson := lessons.Lookup(e.Rule.Lesson); lesson != nil {
+			fmt.Println("  " + utils.Colorize(utils.ColorCyan, i18n.T("explain.lesson", lesson.Title, lesson.ID)))
+		}
+		if term := lessons.LookupTerm(e.Rule.Term); term != nil {
+			fmt.Println("  " + utils.Colorize(utils.ColorGray, i18n.T("explain.term", term.Name)))
+		}
+	}
+}
+
+// benchCommand runs the benchmark lab's suites, all of them or the ones
+// named, and prints the results
+func benchCommand(args []string) int {
+	suites := bench.Suites
+	if len(args) > 0 {
+		suites = nil
+		for _, id := range args {
+			suite := bench.LookupSuite(id)
+			if suite == nil {
+				var ids []string
+				for _, s := range bench.Suites {
+					ids = append(ids, s.ID)
+				}
+				fmt.Fprintln(os.Stderr, i18n.T("bench.no_suite", id, strings.Join(ids, ", ")))
+				return 2
+			}
+			suites = append(suites, suite)
+		}
+	}
+	for _, suite := range suites {
+		for _, line := range benchmarkLines(suite, runSuite(suite)) {
+			fmt.Println(line)
+		}
+	}
+	return 0
+}
+
+// escapeCommand shows the escape analysis of a lesson's code or of a Go file
+func escapeCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T("escape.usage"))
+		return 2
+	}
+	var report *escape.Report
+	var err error
+	title := args[0]
+	if lesson := lessons.Lookup(args[0]); lesson != nil {
+		title = lesson.DisplayTitle()
+		report, err = escape.Lesson(lesson)
+	} else {
+		var code []byte
+		if code, err = os.ReadFile(args[0]); err == nil {
+			report, err = escape.Analyze(string(code))
+		}
+	}
+	if printEscapeError(err) {
+		return 1
+	}
+	for _, line := range escapeLines(title, report) {
+		fmt.Println(line)
+	}
+	return 0
+}
+
+// inspectCommand checks that the interface inspector reads interface values
+// correctly with the Go version and platform the explorer was built for
+func inspectCommand(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("inspect.usage"))
+		return 2
+	}
+	platform := runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH
+	if err := inspect.Verify(); err != nil {
+		fmt.Println(i18n.T("inspect.failed", platform, err))
+		return 1
+	}
+	fmt.Println(i18n.T("inspect.verified", platform))
+	return 0
+}
+
+// checkCommand runs the static checks on the Go package in a directory, the
+// current one by default, and exits with status 1 when they find problems
+func checkCommand(args []string) int {
+	dir := "."
+	switch len(args) {
+	case 0:
+	case 1:
+		dir = args[0]
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T("check.usage"))
+		return 2
+	}
+	findings, err := checks.Dir(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "check: %v\n", err)
+		return 2
+	}
+	if len(findings) == 0 {
+		fmt.Println(i18n.T("check.none", dir))
+		return 0
+	}
+	for _, f := range findings {
+		fmt.Println()
+		fmt.Println(utils.Colorize(utils.ColorRed, fmt.Sprintf("%s:%d: ", f.Pos.Filename, f.Pos.Line)+i18n.T("check.in", f.Func)))
+		fmt.Println("  " + f.Message)
+		for _, l := range f.Excerpt {
+			line := fmt.Sprintf("  %4d | %s", l.Number, l.Text)
+			if l.Marked {
+				line = utils.Colorize(utils.ColorYellow, ">"+line[1:])
+			} else {
+				line = utils.Colorize(utils.ColorGray, line)
+			}
+			fmt.Println(line)
+		}
+		fmt.Println("  " + i18n.T("explain.fix", f.Fix))
+	}
+	fmt.Println()
+	if len(findings) == 1 {
+		fmt.Println(i18n.T("check.found_one"))
+	} else {
+		fmt.Println(i18n.T("check.found", len(findings)))
+	}
+	return 1
+}
+
+// drawCommand draws shapes to an SVG file: the shapes in a JSON file, in
+// the format of the Interfaces and JSON lesson, or the lesson gallery
+func drawCommand(args []string) int {
+	fs := flag.NewFlagSet("draw", flag.ContinueOnError)
+	out := fs.String("out", "shapes.svg", "output path")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	shapes := examples.ShapeGallery()
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		data, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "draw: %v\n", err)
+			return 1
+		}
+		shapes, err = examples.ShapeCodec.UnmarshalSlice(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "draw: %s: %v\n", fs.Arg(0), err)
+			return 1
+		}
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T("draw.usage"))
+		return 2
+	}
+
+	skipped, err := svg.WriteFile(*out, shapes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "draw: %v\n", err)
+		return 1
+	}
+	for _, i := range skipped {
+		fmt.Println(utils.Colorize(utils.ColorYellow, i18n.T("draw.skipped", i, fmt.Sprintf("%T", shapes[i]))))
+	}
+	fmt.Println(i18n.T("draw.wrote", len(shapes)-len(skipped), *out))
+	return 0
+}
+
+// conformCommand runs the conformance checks on every writer of the
+// pipeline package and exits with status 1 when one breaks the contract
+func conformCommand(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("conform.usage"))
+		return 2
+	}
+	dir, err := os.MkdirTemp("", "explorer-conform")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "conform: %v\n", err)
+		return 2
+	}
+	defer os.RemoveAll(dir)
+
+	subjects := pipeline.Subjects(dir)
+	failed := 0
+	for _, s := range subjects {
+		errs := pipeline.Conformance(s)
+		if len(errs) == 0 {
+			fmt.Println(utils.Colorize(utils.ColorGreen, i18n.T("conform.pass")) + " " + s.Name)
+			continue
+		}
+		failed++
+		fmt.Println(utils.Colorize(utils.ColorRed, i18n.T("conform.fail")) + " " + s.Name)
+		for _, err := range errs {
+			fmt.Println("  " + err.Error())
+		}
+	}
+	if failed > 0 {
+		fmt.Println(i18n.T("conform.failed", failed, len(subjects)))
+		return 1
+	}
+	fmt.Println(i18n.T("conform.passed", len(subjects)))
+	return 0
+}
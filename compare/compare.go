@@ -0,0 +1,73 @@
+package compare
+
+import (
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/utils"
+)
+
+// Context is the number of unchanged lines shown around each change
+const Context = 3
+
+// Change is a concept the second lesson introduces. When it takes over
+// from a concept the first lesson used and the second one no longer does,
+// such as typed constants replacing untyped ones, Replaces is that concept.
+type Change struct {
+	Concept  *Concept
+	Replaces *Concept
+}
+
+// Comparison is what changed in the code from one lesson to another
+type Comparison struct {
+	From, To       *lessons.Lesson
+	Lines          []Line
+	Hunks          []Hunk
+	Added, Removed int
+	Introduced     []Change
+	Dropped        []*Concept
+}
+
+// Lessons compares the code samples of two lessons. Callout markers are
+// left out, so only the code itself is compared.
+func Lessons(from, to *lessons.Lesson) *Comparison {
+	oldCode, _ := utils.StripCallouts(from.Section(utils.SectionCode))
+	newCode, _ := utils.StripCallouts(to.Section(utils.SectionCode))
+
+	c := &Comparison{From: from, To: to, Lines: Lines(oldCode, newCode)}
+	c.Hunks = Hunks(c.Lines, Context)
+	for _, l := range c.Lines {
+		switch l.Op {
+		case Insert:
+			c.Added++
+		case Delete:
+			c.Removed++
+		}
+	}
+
+	before := make(map[string]bool)
+	for _, concept := range Used(oldCode) {
+		before[concept.ID] = true
+	}
+	after := make(map[string]bool)
+	for _, concept := range Used(newCode) {
+		after[concept.ID] = true
+	}
+
+	replaced := make(map[string]bool)
+	for _, concept := range Concepts {
+		if !after[concept.ID] || before[concept.ID] {
+			continue
+		}
+		change := Change{Concept: concept}
+		if concept.Replaces != "" && before[concept.Replaces] && !after[concept.Replaces] {
+			change.Replaces = LookupConcept(concept.Replaces)
+			replaced[concept.Replaces] = true
+		}
+		c.Introduced = append(c.Introduced, change)
+	}
+	for _, concept := range Concepts {
+		if before[concept.ID] && !after[concept.ID] && !replaced[concept.ID] {
+			c.Dropped = append(c.Dropped, concept)
+		}
+	}
+	return c
+}
@@ -0,0 +1,184 @@
+package compare
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// Concept is a language feature that lesson code can use
+type Concept struct {
+	ID          string
+	Name        string
+	Description string
+	Term        string // ID of the glossary term that explains it, if any
+	Replaces    string // ID of the concept it usually takes over from
+}
+
+// Concepts lists the features the comparison looks for, in the order they
+// are reported
+var Concepts = []*Concept{
+	{ID: "untyped-constants", Name: "Untyped constants", Description: "Plain constants with no type of their own, so any int is accepted where one is expected."},
+	{ID: "named-type", Name: "Named type", Description: "A new type defined over a basic type such as int, which the constants and methods belong to."},
+	{ID: "typed-constants", Name: "Typed constants", Description: "Constants declared with a named type, so the compiler rejects values of other types.", Term: "type-safety", Replaces: "untyped-constants"},
+	{ID: "iota", Name: "iota", Description: "Numbers the constants of a block automatically instead of writing each value.", Term: "iota"},
+	{ID: "bit-flags", Name: "Bit flags", Description: "Shifting 1 << iota gives each constant its own bit so values can be combined."},
+	{ID: "string-method", Name: "String method", Description: "A String() string method makes the type a fmt.Stringer, so values print as names.", Term: "fmt-stringer"},
+	{ID: "lookup-table", Name: "Lookup table", Description: "An array, slice or map of strings that maps values to names."},
+	{ID: "methods", Name: "Methods", Description: "Behavior attached to a type through methods other than String."},
+	{ID: "pointer-receiver", Name: "Pointer receivers", Description: "Methods on *T, which can change the value and are only in the method set of the pointer.", Term: "method-set"},
+	{ID: "switch", Name: "Switch statement", Description: "Choosing what to do by comparing a value against cases."},
+	{ID: "struct-type", Name: "Struct types", Description: "Types that group named fields."},
+	{ID: "struct-embedding", Name: "Struct embedding", Description: "A struct field without a name, whose methods are promoted to the outer struct."},
+	{ID: "interface-type", Name: "Interface type", Description: "A set of method signatures that every type with those methods satisfies.", Term: "implicit-implementation"},
+	{ID: "embedded-interface", Name: "Embedded interfaces", Description: "An interface built from other interfaces.", Term: "interface-composition"},
+	{ID: "empty-interface", Name: "Empty interface", Description: "interface{} or any, which holds a value of any type.", Term: "empty-interface"},
+	{ID: "type-assertion", Name: "Type assertion", Description: "x.(T) extracts the concrete value stored in an interface.", Term: "type-assertion"},
+	{ID: "comma-ok", Name: "Comma ok", Description: "The two-value form that reports whether an assertion succeeded instead of panicking.", Term: "comma-ok"},
+	{ID: "type-switch", Name: "Type switch", Description: "A switch on the dynamic type of an interface value.", Term: "type-switch"},
+}
+
+// LookupConcept finds a concept by ID
+func LookupConcept(id string) *Concept {
+	for _, c := range Concepts {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// basicTypes are the predeclared types an enum type is usually defined over
+var basicTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"byte": true, "rune": true, "string": true, "float32": true, "float64": true,
+}
+
+// Used returns the concepts the code uses, in the order of Concepts. Lesson
+// code has no package clause, so one is added before parsing; code that
+// does not parse completely is analyzed as far as the parser got.
+func Used(code string) []*Concept {
+	fset := token.NewFileSet()
+	file, _ := parser.ParseFile(fset, "lesson.go", "package lesson\n"+code, 0)
+	if file == nil {
+		return nil
+	}
+
+	found := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			if n.Tok == token.CONST {
+				for _, spec := range n.Specs {
+					v := spec.(*ast.ValueSpec)
+					if v.Type != nil {
+						found["typed-constants"] = true
+					} else if len(v.Values) > 0 {
+						found["untyped-constants"] = true
+					}
+				}
+			}
+		case *ast.TypeSpec:
+			switch t := n.Type.(type) {
+			case *ast.Ident:
+				if basicTypes[t.Name] {
+					found["named-type"] = true
+				}
+			case *ast.StructType:
+				found["struct-type"] = true
+			case *ast.InterfaceType:
+				if len(t.Methods.List) > 0 {
+					found["interface-type"] = true
+				}
+			}
+		case *ast.StructType:
+			for _, f := range n.Fields.List {
+				if len(f.Names) == 0 {
+					found["struct-embedding"] = true
+				}
+			}
+		case *ast.InterfaceType:
+			if len(n.Methods.List) == 0 {
+				found["empty-interface"] = true
+			}
+			for _, f := range n.Methods.List {
+				if len(f.Names) == 0 {
+					found["embedded-interface"] = true
+				}
+			}
+		case *ast.Ident:
+			switch n.Name {
+			case "iota":
+				found["iota"] = true
+			case "any":
+				found["empty-interface"] = true
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.SHL && (isIota(n.X) || isIota(n.Y)) {
+				found["bit-flags"] = true
+			}
+		case *ast.FuncDecl:
+			if n.Recv == nil || len(n.Recv.List) == 0 {
+				break
+			}
+			if _, ok := n.Recv.List[0].Type.(*ast.StarExpr); ok {
+				found["pointer-receiver"] = true
+			}
+			if isStringMethod(n) {
+				found["string-method"] = true
+			} else {
+				found["methods"] = true
+			}
+		case *ast.CompositeLit:
+			var elt ast.Expr
+			switch t := n.Type.(type) {
+			case *ast.ArrayType:
+				elt = t.Elt
+			case *ast.MapType:
+				elt = t.Value
+			}
+			if id, ok := elt.(*ast.Ident); ok && id.Name == "string" {
+				found["lookup-table"] = true
+			}
+		case *ast.TypeAssertExpr:
+			// x.(type) in a type switch has no type
+			if n.Type != nil {
+				found["type-assertion"] = true
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) == 2 && len(n.Rhs) == 1 {
+				if _, ok := n.Rhs[0].(*ast.TypeAssertExpr); ok {
+					found["comma-ok"] = true
+				}
+			}
+		case *ast.TypeSwitchStmt:
+			found["type-switch"] = true
+		case *ast.SwitchStmt:
+			found["switch"] = true
+		}
+		return true
+	})
+
+	var used []*Concept
+	for _, c := range Concepts {
+		if found[c.ID] {
+			used = append(used, c)
+		}
+	}
+	return used
+}
+
+func isIota(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "iota"
+}
+
+// isStringMethod reports whether a method is String() string
+func isStringMethod(f *ast.FuncDecl) bool {
+	if f.Name.Name != "String" || f.Type.Params.NumFields() != 0 || f.Type.Results.NumFields() != 1 {
+		return false
+	}
+	id, ok := f.Type.Results.List[0].Type.(*ast.Ident)
+	return ok && id.Name == "string"
+}
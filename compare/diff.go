@@ -0,0 +1,140 @@
+// Package compare shows what changed between the code of two lessons: a
+// line diff of the code samples and the language concepts the second one
+// introduces, so progressions such as Basic Enums → Iota Enums → String
+// Enums can be read as a series of small steps.
+package compare
+
+import "strings"
+
+// Op says what happened to a line between the two texts
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+)
+
+// Line is one line of a diff. Old and New are its line numbers in the two
+// texts, counting from 1, or 0 when the line is not in that text.
+type Line struct {
+	Op       Op
+	Text     string
+	Old, New int
+}
+
+// Lines diffs two texts line by line with a longest common subsequence.
+// Lines that only differ in indentation or spacing count as equal, so
+// re-indented code does not show up as a change.
+func Lines(a, b string) []Line {
+	left := strings.Split(a, "\n")
+	right := strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// left[i:] and right[j:]
+	lcs := make([][]int, len(left)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(right)+1)
+	}
+	for i := len(left) - 1; i >= 0; i-- {
+		for j := len(right) - 1; j >= 0; j-- {
+			if sameLine(left[i], right[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(left) || j < len(right) {
+		switch {
+		case i < len(left) && j < len(right) && sameLine(left[i], right[j]):
+			lines = append(lines, Line{Op: Equal, Text: right[j], Old: i + 1, New: j + 1})
+			i++
+			j++
+		case i < len(left) && (j == len(right) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Removals come before the additions that replace them
+			lines = append(lines, Line{Op: Delete, Text: left[i], Old: i + 1})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: right[j], New: j + 1})
+			j++
+		}
+	}
+	return lines
+}
+
+func sameLine(a, b string) bool {
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
+
+// Hunk is a run of changed lines with some unchanged lines around them
+type Hunk struct {
+	Lines []Line
+}
+
+// Hunks groups the changes of a diff, keeping context unchanged lines on
+// either side of each change. Changes closer than twice the context share
+// a hunk.
+func Hunks(lines []Line, context int) []Hunk {
+	var hunks []Hunk
+	var current *Hunk
+	lastChange := -1
+	for i, l := range lines {
+		if l.Op == Equal {
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		if current != nil && start <= lastChange+context+1 {
+			current.Lines = append(current.Lines, lines[lastChange+1:i+1]...)
+		} else {
+			if current != nil {
+				current.Lines = append(current.Lines, trailing(lines, lastChange, context)...)
+				hunks = append(hunks, *current)
+			}
+			current = &Hunk{Lines: append([]Line(nil), lines[start:i+1]...)}
+		}
+		lastChange = i
+	}
+	if current != nil {
+		current.Lines = append(current.Lines, trailing(lines, lastChange, context)...)
+		hunks = append(hunks, *current)
+	}
+	return hunks
+}
+
+// trailing returns up to context lines after the change at index last
+func trailing(lines []Line, last, context int) []Line {
+	end := last + 1 + context
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return lines[last+1 : end]
+}
+
+// Span returns the first line and the number of lines the hunk covers in
+// the old and the new text
+func (h Hunk) Span() (oldStart, oldCount, newStart, newCount int) {
+	for _, l := range h.Lines {
+		if l.Old > 0 {
+			if oldStart == 0 {
+				oldStart = l.Old
+			}
+			oldCount++
+		}
+		if l.New > 0 {
+			if newStart == 0 {
+				newStart = l.New
+			}
+			newCount++
+		}
+	}
+	return oldStart, oldCount, newStart, newCount
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-interface-enum-explorer/compare"
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/utils"
+)
+
+// compareLessons lets the learner pick two lessons and shows what changed
+// in the code from the first to the second
+func compareLessons(scanner *bufio.Scanner) {
+	for {
+		clearScreen()
+		utils.PrintColoredTitle(i18n.T("compare.menu_title"), utils.ColorCyan)
+
+		allLessons := lessons.All()
+		for i, lesson := range allLessons {
+			fmt.Printf("%d. %s\n", i+1, lesson.DisplayTitle())
+		}
+		fmt.Println("b. " + i18n.T("menu.back"))
+
+		fmt.Print("\n" + i18n.T("prompt.compare_from"))
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
+
+		if handleSearch(scanner, choice) {
+			continue
+		}
+		if choice == "b" || choice == "B" {
+			break
+		}
+		from := pickLesson(allLessons, choice)
+		if from == nil {
+			fmt.Println(i18n.T("error.invalid_selection"))
+			utils.PressEnterToContinue()
+			continue
+		}
+
+		fmt.Print(i18n.T("prompt.compare_to"))
+		scanner.Scan()
+		choice = strings.TrimSpace(scanner.Text())
+		to := pickLesson(allLessons, choice)
+		if choice == "" {
+			to = nextInCategory(allLessons, from)
+		}
+		if to == nil {
+			fmt.Println(i18n.T("error.invalid_selection"))
+			utils.PressEnterToContinue()
+			continue
+		}
+
+		clearScreen()
+		doc := &utils.Document{Lines: comparisonLines(compare.Lessons(from, to))}
+		doc.Show()
+		utils.PressEnterToContinue()
+	}
+}
+
+// pickLesson finds a lesson by its number in the list, its ID or its title
+func pickLesson(list []*lessons.Lesson, choice string) *lessons.Lesson {
+	if index, err := strconv.Atoi(choice); err == nil {
+		if index >= 1 && index <= len(list) {
+			return list[index-1]
+		}
+		return nil
+	}
+	if choice == "" {
+		return nil
+	}
+	return lessons.Lookup(choice)
+}
+
+// nextInCategory returns the lesson after l in the same category, which is
+// usually the next step of a progression such as Basic Enums → Iota Enums
+func nextInCategory(list []*lessons.Lesson, l *lessons.Lesson) *lessons.Lesson {
+	seen := false
+	for _, other := range list {
+		if other == l {
+			seen = true
+		} else if seen && other.Category == l.Category {
+			return other
+		}
+	}
+	return nil
+}
+
+// comparisonLines lays out a comparison for the terminal: the concepts the
+// second lesson introduces and drops, then the changed code with a few
+// lines of context, additions in green and removals in red
+func comparisonLines(c *compare.Comparison) []string {
+	var lines []string
+	title := i18n.T("compare.title", c.From.DisplayTitle(), c.To.DisplayTitle())
+	if utils.Accessible() {
+		lines = append(lines, title, "")
+	} else {
+		rule := utils.Colorize(utils.ColorCyan, "===================================")
+		lines = append(lines, rule, utils.Colorize(utils.ColorCyan, title), rule, "")
+	}
+
+	lines = append(lines, utils.Colorize(utils.ColorMagenta, i18n.T("compare.introduced", c.To.DisplayTitle())))
+	if len(c.Introduced) == 0 {
+		lines = append(lines, "  "+i18n.T("compare.nothing_new"))
+	}
+	for _, change := range c.Introduced {
+		line := "  + " + utils.Colorize(utils.ColorGreen, change.Concept.Name)
+		if change.Replaces != nil {
+			line += " " + i18n.T("compare.replaces", change.Replaces.Name)
+		}
+		lines = append(lines, line+": "+change.Concept.Description)
+	}
+	if len(c.Dropped) > 0 {
+		var names []string
+		for _, concept := range c.Dropped {
+			names = append(names, concept.Name)
+		}
+		lines = append(lines, "", utils.Colorize(utils.ColorMagenta, i18n.T("compare.dropped")), "  "+strings.Join(names, ", "))
+	}
+
+	lines = append(lines, "", utils.Colorize(utils.ColorMagenta, i18n.T("compare.stats", c.Added, c.Removed)))
+	if len(c.Hunks) == 0 {
+		lines = append(lines, "  "+i18n.T("compare.same"))
+	}
+	for _, hunk := range c.Hunks {
+		oldStart, oldCount, newStart, newCount := hunk.Span()
+		lines = append(lines, "", utils.Colorize(utils.ColorCyan, i18n.T("compare.hunk", oldStart, oldStart+oldCount-1, newStart, newStart+newCount-1)))
+		for _, l := range hunk.Lines {
+			lines = append(lines, diffLine(l))
+		}
+	}
+	return lines
+}
+
+// diffLine formats one line of the diff, in words when a screen reader is
+// reading it
+func diffLine(l compare.Line) string {
+	text := strings.ReplaceAll(l.Text, "\t", "    ")
+	if utils.Accessible() {
+		switch l.Op {
+		case compare.Insert:
+			return i18n.T("a11y.added", l.New, strings.TrimSpace(text))
+		case compare.Delete:
+			return i18n.T("a11y.removed", l.Old, strings.TrimSpace(text))
+		default:
+			return i18n.T("a11y.line", l.New, strings.TrimSpace(text))
+		}
+	}
+	switch l.Op {
+	case compare.Insert:
+		return utils.Colorize(utils.ColorGreen, "+ "+text)
+	case compare.Delete:
+		return utils.Colorize(utils.ColorRed, "- "+text)
+	default:
+		return "  " + text
+	}
+}
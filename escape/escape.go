@@ -0,0 +1,163 @@
+// Package escape runs the compiler's escape analysis on lesson code and
+// reports, line by line, which values are moved to the heap and which stay
+// on the stack. Converting a value to an interface, as in printAny(42), is
+// the most common reason a lesson's values escape.
+package escape
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/sandbox"
+	"go-interface-enum-explorer/snippet"
+	"go-interface-enum-explorer/utils"
+)
+
+// Kind is what the compiler decided about a value
+type Kind int
+
+const (
+	// Escapes means the value is allocated on the heap
+	Escapes Kind = iota
+	// MovedToHeap means a variable is allocated on the heap instead of
+	// the stack
+	MovedToHeap
+	// Leaks means a parameter outlives the call, so arguments passed to it
+	// may have to be allocated on the heap by the caller
+	Leaks
+	// DoesNotEscape means the value stays on the stack
+	DoesNotEscape
+)
+
+// Heap reports whether the kind means a heap allocation
+func (k Kind) Heap() bool {
+	return k == Escapes || k == MovedToHeap
+}
+
+// Name returns the key of the kind, as used in the message catalog
+func (k Kind) Name() string {
+	return [...]string{"escapes", "moved", "leaks", "stays"}[k]
+}
+
+// Note is one escape analysis decision about a value in the code
+type Note struct {
+	Line, Column int // position in the lesson code, not the program
+	Kind         Kind
+	Subject      string // the expression or variable, such as 42 or item
+	Message      string // the compiler's message
+}
+
+// Report is the escape analysis of one piece of code
+type Report struct {
+	Code  string
+	Notes []Note
+}
+
+// Line returns the notes about line n of the code, in column order
+func (r *Report) Line(n int) []Note {
+	var notes []Note
+	for _, note := range r.Notes {
+		if note.Line == n {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// Heap returns the number of values the code allocates on the heap
+func (r *Report) Heap() int {
+	count := 0
+	for _, note := range r.Notes {
+		if note.Kind.Heap() {
+			count++
+		}
+	}
+	return count
+}
+
+// Lesson analyzes the code sample of a lesson. Callout markers are left out,
+// so the line numbers match the code as it is displayed.
+func Lesson(l *lessons.Lesson) (*Report, error) {
+	code, _ := utils.StripCallouts(l.Section(utils.SectionCode))
+	return Analyze(code)
+}
+
+// Analyze compiles code with -gcflags=-m, completing it into a program the
+// way the sandbox does, and collects the compiler's escape decisions. Code
+// that does not compile returns a *sandbox.CompileError.
+func Analyze(code string) (*Report, error) {
+	code = strings.Trim(code, "\n")
+	out, err := sandbox.Build([]byte(snippet.Program(code)), "-m")
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Code: code, Notes: Parse(out, snippet.Offset(code), strings.Count(code, "\n")+1)}, nil
+}
+
+var (
+	diagnosticPattern = regexp.MustCompile(`^(?:\./)?main\.go:(\d+):(\d+): (.*)$`)
+	escapesPattern    = regexp.MustCompile(`^(.+) escapes to heap(?::.*)?$`)
+	movedPattern      = regexp.MustCompile(`^moved to heap: (.+)$`)
+	leakingPattern    = regexp.MustCompile(`^leaking param(?: content)?: (\S+)`)
+	staysPattern      = regexp.MustCompile(`^(.+) does not escape$`)
+)
+
+// Parse reads the output of the compiler's -m flag for a program whose
+// first offset lines come before the code, keeping the decisions about the
+// code's lines 1 to lines. Inlining decisions and repeated messages are
+// left out.
+func Parse(output string, offset, lines int) []Note {
+	var notes []Note
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		m := diagnosticPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		column, _ := strconv.Atoi(m[2])
+		n -= offset
+		if n < 1 || n > lines {
+			continue
+		}
+		note := Note{Line: n, Column: column, Message: m[3]}
+		switch {
+		case matchInto(escapesPattern, m[3], &note.Subject):
+			note.Kind = Escapes
+		case matchInto(movedPattern, m[3], &note.Subject):
+			note.Kind = MovedToHeap
+		case matchInto(leakingPattern, m[3], &note.Subject):
+			note.Kind = Leaks
+		case matchInto(staysPattern, m[3], &note.Subject):
+			note.Kind = DoesNotEscape
+		default:
+			continue
+		}
+		key := strconv.Itoa(n) + ":" + note.Kind.Name() + ":" + note.Subject
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		notes = append(notes, note)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].Line != notes[j].Line {
+			return notes[i].Line < notes[j].Line
+		}
+		return notes[i].Column < notes[j].Column
+	})
+	return notes
+}
+
+// matchInto matches s and stores the first group in subject
+func matchInto(pattern *regexp.Regexp, s string, subject *string) bool {
+	m := pattern.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	*subject = m[1]
+	return true
+}
@@ -0,0 +1,32 @@
+package escape
+
+// Explanations say in plain language what each kind of decision means for
+// the program, and why interfaces are so often the cause
+var Explanations = map[Kind]string{
+	Escapes: `The value is allocated on the heap instead of the stack. The garbage
+collector has to find and free it later, which costs more than a stack
+allocation that disappears when the function returns.
+
+Converting a value to an interface is the most common cause in the lessons:
+an interface holds a pointer to its value, so printAny(42) or
+push(&stack, 42) copy 42 to the heap first. Constants and small integers
+(0 to 255) are an exception at run time: Go points the interface at data
+that already exists, so the compiler's "escapes" does not always mean an
+allocation happens. A variable stored in an interface{} always costs one.
+
+Appending to a slice that must outlive the call (like the items of a stack)
+also escapes, because the slice's backing array has to stay alive.`,
+
+	MovedToHeap: `A variable is allocated on the heap because something keeps a pointer to
+it after the function returns, for example its address is returned, stored
+in a struct or captured by a closure that outlives the call.`,
+
+	Leaks: `The parameter outlives the call: the function stores it, returns it or
+puts it in an interface. Callers then have to allocate the arguments they
+pass on the heap. Parameters of type interface{} leak whenever the function
+hands them on to something like fmt.Printf.`,
+
+	DoesNotEscape: `The value stays on the stack, so it costs no garbage collection. The
+"... argument" of a call such as fmt.Printf is the slice that holds the
+variadic arguments; it does not escape, although the values in it may.`,
+}
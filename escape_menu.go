@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"go-interface-enum-explorer/escape"
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/sandbox"
+	"go-interface-enum-explorer/utils"
+)
+
+// showEscapes runs escape analysis on a lesson's code and shows the code
+// with the compiler's decisions under each line
+func showEscapes(l *lessons.Lesson) {
+	fmt.Println(utils.Colorize(utils.ColorGray, i18n.T("escape.running", l.DisplayTitle())))
+	report, err := escape.Lesson(l)
+	if printEscapeError(err) {
+		return
+	}
+	clearScreen()
+	doc := &utils.Document{Lines: escapeLines(l.DisplayTitle(), report)}
+	doc.Show()
+}
+
+// printEscapeError shows why the analysis could not be done, if it failed
+func printEscapeError(err error) bool {
+	if compileErr, ok := err.(*sandbox.CompileError); ok {
+		fmt.Println(i18n.T("run.compile_failed"))
+		fmt.Println(utils.Colorize(utils.ColorRed, compileErr.Output))
+		return true
+	}
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	return false
+}
+
+// escapeMarkerColors shows heap allocations in red, leaking parameters in
+// yellow and values that stay on the stack in green
+var escapeMarkerColors = map[escape.Kind]string{
+	escape.Escapes:       utils.ColorRed,
+	escape.MovedToHeap:   utils.ColorRed,
+	escape.Leaks:         utils.ColorYellow,
+	escape.DoesNotEscape: utils.ColorGreen,
+}
+
+// escapeLines lays out an escape analysis: the code with a marker under
+// each line for every decision about it, then what the markers mean
+func escapeLines(title string, r *escape.Report) []string {
+	var lines []string
+	heading := i18n.T("escape.title", title)
+	if utils.Accessible() {
+		lines = append(lines, heading, "")
+	} else {
+		rule := utils.Colorize(utils.ColorCyan, "===================================")
+		lines = append(lines, rule, utils.Colorize(utils.ColorCyan, heading), rule, "")
+	}
+	lines = append(lines, i18n.T("escape.summary", r.Heap(), len(r.Notes)-r.Heap()), "")
+
+	used := make(map[escape.Kind]bool)
+	for i, code := range utils.HighlightCode(r.Code, true) {
+		lines = append(lines, code)
+		for _, note := range r.Line(i + 1) {
+			used[note.Kind] = true
+			marker := i18n.T("escape.marker."+note.Kind.Name(), note.Subject)
+			lines = append(lines, "        "+utils.Colorize(escapeMarkerColors[note.Kind], marker))
+		}
+	}
+
+	lines = append(lines, "", utils.Colorize(utils.ColorMagenta, i18n.T("escape.meaning")))
+	for _, kind := range []escape.Kind{escape.Escapes, escape.MovedToHeap, escape.Leaks, escape.DoesNotEscape} {
+		if !used[kind] {
+			continue
+		}
+		lines = append(lines, "", utils.Colorize(escapeMarkerColors[kind], i18n.T("escape.marker."+kind.Name(), "…")))
+		lines = append(lines, strings.Split(escape.Explanations[kind], "\n")...)
+	}
+	if term := lessons.LookupTerm("escape-analysis"); term != nil {
+		lines = append(lines, "", utils.Colorize(utils.ColorGray, i18n.T("explain.term", term.Name)))
+	}
+	return lines
+}
@@ -2,6 +2,8 @@ package examples
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
 	"go-interface-enum-explorer/utils"
 )
@@ -12,10 +14,13 @@ type BasicShape interface {
 	Perimeter() float64
 }
 
-// Define Rectangle for the BasicInterfaces example
+// Define Rectangle for the BasicInterfaces example. X and Y place its
+// lower left corner.
 type BasicRectangle struct {
-	Width  float64
-	Height float64
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 func (r BasicRectangle) Area() float64 {
@@ -26,17 +31,25 @@ func (r BasicRectangle) Perimeter() float64 {
 	return 2 * (r.Width + r.Height)
 }
 
-// Define Circle for the BasicInterfaces example
+// Define Circle for the BasicInterfaces example. X and Y place its center.
 type BasicCircle struct {
-	Radius float64
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+	Radius float64 `json:"radius"`
 }
 
 func (c BasicCircle) Area() float64 {
-	return 3.14159 * c.Radius * c.Radius
+	return math.Pi * c.Radius * c.Radius
 }
 
 func (c BasicCircle) Perimeter() float64 {
-	return 2 * 3.14159 * c.Radius
+	return 2 * math.Pi * c.Radius
+}
+
+// printShapeInfo takes any BasicShape and prints information about it
+func printShapeInfo(s BasicShape) {
+	fmt.Printf("Area: %.2f\n", s.Area())
+	fmt.Printf("Perimeter: %.2f\n", s.Perimeter())
 }
 
 // BasicInterfaces demonstrates the fundamental concepts of interfaces in Go
@@ -49,11 +62,22 @@ An interface in Go is a collection of method signatures that a type can implemen
 It defines behavior, not structure. Any type that implements all the methods
 of an interface implicitly satisfies that interface.
 
+Because any type with the right methods is a Shape, new shapes can be added
+without changing the code that uses Shape. Extra behavior that only some
+shapes have, such as being resized, is described by a second, smaller
+interface; code asks a Shape whether it also has that behavior with a type
+assertion (covered in detail in the Type Assertion lesson).
+
+The Square below was written before the extra interfaces existed, so it has
+none of them, and the code works with it anyway. The explorer draw command
+finds the shapes it can draw the same way, by asking for a Bounds method.
+
 Key points:
 - Interfaces define behavior through method signatures
 - Types implement interfaces implicitly (no "implements" keyword)
 - A type can implement multiple interfaces
 - Interfaces allow for polymorphism in Go
+- Optional behavior goes in small extra interfaces, discovered at run time
 `)
 
 	utils.PrintCode(`
@@ -63,86 +87,223 @@ type Shape interface {
         Perimeter() float64
 }
 
-// Rectangle is a concrete type that will implement the Shape interface
-type Rectangle struct {
-        Width  float64
-        Height float64
+// Optional capabilities: only some shapes can be resized, moved or boxed
+type Scaler interface {
+        Scale(factor float64) Shape
 }
 
-// Area calculates the area of a Rectangle
-// This method implements the Area method from the Shape interface
-func (r Rectangle) Area() float64 {
-        return r.Width * r.Height
+type Translator interface {
+        Translate(dx, dy float64) Shape
 }
 
-// Perimeter calculates the perimeter of a Rectangle
-// This method implements the Perimeter method from the Shape interface
-func (r Rectangle) Perimeter() float64 {
-        return 2 * (r.Width + r.Height)
+type Bounder interface {
+        Bounds() (minX, minY, maxX, maxY float64)
 }
 
-// Circle is another concrete type that will implement the Shape interface
+// Rectangle is a concrete type that will implement the Shape interface.
+// X and Y place its lower left corner.
+type Rectangle struct {
+        X, Y, Width, Height float64
+}
+
+// Area and Perimeter implement the Shape interface
+func (r Rectangle) Area() float64      { return r.Width * r.Height }
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+
+// Scale, Translate and Bounds make Rectangle a Scaler, a Translator and
+// a Bounder as well as a Shape
+func (r Rectangle) Scale(factor float64) Shape {
+        return Rectangle{r.X, r.Y, r.Width * factor, r.Height * factor}
+}
+func (r Rectangle) Translate(dx, dy float64) Shape {
+        return Rectangle{r.X + dx, r.Y + dy, r.Width, r.Height}
+}
+func (r Rectangle) Bounds() (float64, float64, float64, float64) {
+        return r.X, r.Y, r.X + r.Width, r.Y + r.Height
+}
+
+// Circle is another Shape with every capability. X and Y place its center.
 type Circle struct {
-        Radius float64
+        X, Y, Radius float64
+}
+
+func (c Circle) Area() float64      { return math.Pi * c.Radius * c.Radius }
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.Radius }
+
+func (c Circle) Scale(factor float64) Shape {
+        return Circle{c.X, c.Y, c.Radius * factor}
+}
+func (c Circle) Translate(dx, dy float64) Shape {
+        return Circle{c.X + dx, c.Y + dy, c.Radius}
+}
+func (c Circle) Bounds() (float64, float64, float64, float64) {
+        return c.X - c.Radius, c.Y - c.Radius, c.X + c.Radius, c.Y + c.Radius
+}
+
+// Triangle is given by its corners. It can be moved and boxed, and it
+// scales around its first corner.
+type Point struct{ X, Y float64 }
+
+type Triangle struct{ A, B, C Point }
+
+// Area uses the shoelace formula on the corners
+func (t Triangle) Area() float64 {
+        return math.Abs(t.A.X*(t.B.Y-t.C.Y)+t.B.X*(t.C.Y-t.A.Y)+t.C.X*(t.A.Y-t.B.Y)) / 2
+}
+
+// Perimeter adds up the lengths of the sides
+func (t Triangle) Perimeter() float64 {
+        return math.Hypot(t.B.X-t.A.X, t.B.Y-t.A.Y) +
+                math.Hypot(t.C.X-t.B.X, t.C.Y-t.B.Y) +
+                math.Hypot(t.A.X-t.C.X, t.A.Y-t.C.Y)
 }
 
-// Area calculates the area of a Circle
-func (c Circle) Area() float64 {
-        return 3.14159 * c.Radius * c.Radius
+func (t Triangle) Scale(factor float64) Shape {
+        scale := func(p Point) Point {
+                return Point{t.A.X + (p.X-t.A.X)*factor, t.A.Y + (p.Y-t.A.Y)*factor}
+        }
+        return Triangle{t.A, scale(t.B), scale(t.C)}
+}
+func (t Triangle) Translate(dx, dy float64) Shape {
+        move := func(p Point) Point { return Point{p.X + dx, p.Y + dy} }
+        return Triangle{move(t.A), move(t.B), move(t.C)}
+}
+func (t Triangle) Bounds() (float64, float64, float64, float64) {
+        return math.Min(t.A.X, math.Min(t.B.X, t.C.X)), math.Min(t.A.Y, math.Min(t.B.Y, t.C.Y)),
+                math.Max(t.A.X, math.Max(t.B.X, t.C.X)), math.Max(t.A.Y, math.Max(t.B.Y, t.C.Y))
 }
 
-// Perimeter calculates the perimeter of a Circle
-func (c Circle) Perimeter() float64 {
-        return 2 * 3.14159 * c.Radius
+// Square was written before the capabilities existed: it is a Shape and
+// nothing more
+type Square struct {
+        Side float64
 }
 
+func (s Square) Area() float64      { return s.Side * s.Side }
+func (s Square) Perimeter() float64 { return 4 * s.Side }
+
 // PrintShapeInfo takes a Shape interface and prints information about it
 func PrintShapeInfo(s Shape) {
         fmt.Printf("Area: %.2f\n", s.Area())
         fmt.Printf("Perimeter: %.2f\n", s.Perimeter())
 }
 
+// name returns the name of a shape's type, such as Circle
+func name(s Shape) string {
+        return strings.TrimPrefix(fmt.Sprintf("%T", s), "main.")
+}
+
 func main() {
-        // Create a Rectangle instance
-        rect := Rectangle{Width: 5, Height: 4}
-        
-        // Create a Circle instance
-        circle := Circle{Radius: 3}
-        
-        fmt.Println("Rectangle:")
-        PrintShapeInfo(rect)
-        
-        fmt.Println("\nCircle:")
-        PrintShapeInfo(circle)
+        shapes := []Shape{
+                Rectangle{Width: 5, Height: 4},
+                Circle{X: 9, Y: 2, Radius: 3},
+                Triangle{Point{13, 0}, Point{17, 0}, Point{15, 4}},
+                Square{Side: 2},
+        }
+
+        // Every kind of shape, all used through the same Shape interface
+        for _, s := range shapes {
+                fmt.Printf("%s:\n", name(s))
+                PrintShapeInfo(s)
+                fmt.Println()
+        }
+
+        // Discover the optional capabilities with type assertions
+        fmt.Println("Optional capabilities, discovered with type assertions:")
+        yesNo := map[bool]string{true: "yes", false: "-"}
+        fmt.Printf("  %-10s %-7s %-10s %s\n", "Shape", "Scaler", "Translator", "Bounder")
+        for _, s := range shapes {
+                _, scales := s.(Scaler)
+                _, moves := s.(Translator)
+                _, bounded := s.(Bounder)
+                fmt.Printf("  %-10s %-7s %-10s %s\n", name(s), yesNo[scales], yesNo[moves], yesNo[bounded])
+        }
+
+        // Use a capability when it is there, and do without it otherwise
+        fmt.Println("\nEach shape doubled in size and moved up by 10, where it can be:")
+        for _, s := range shapes {
+                changed := s
+                if scaler, ok := changed.(Scaler); ok {
+                        changed = scaler.Scale(2)
+                }
+                if translator, ok := changed.(Translator); ok {
+                        changed = translator.Translate(0, 10)
+                }
+                if b, ok := changed.(Bounder); ok {
+                        minX, minY, maxX, maxY := b.Bounds()
+                        fmt.Printf("  %-10s area %6.2f → %6.2f, now within (%.1f, %.1f)-(%.1f, %.1f)\n",
+                                name(s), s.Area(), changed.Area(), minX, minY, maxX, maxY)
+                } else {
+                        fmt.Printf("  %-10s area %6.2f, unchanged: it has none of the capabilities\n", name(s), s.Area())
+                }
+        }
 }
 `)
 
 	// Actual implementation
 	utils.PrintOutput("Running the code...")
 
-	// Function that uses the interface
-	printShapeInfo := func(s BasicShape) {
-		fmt.Printf("Area: %.2f\n", s.Area())
-		fmt.Printf("Perimeter: %.2f\n", s.Perimeter())
+	// The shapes of the code above, as the examples package defines them
+	shapes := []BasicShape{
+		BasicRectangle{Width: 5, Height: 4},
+		BasicCircle{X: 9, Y: 2, Radius: 3},
+		BasicTriangle{A: BasicPoint{X: 13, Y: 0}, B: BasicPoint{X: 17, Y: 0}, C: BasicPoint{X: 15, Y: 4}},
+		BasicSquare{Side: 2},
 	}
 
-	// Create a Rectangle
-	rect := BasicRectangle{Width: 5, Height: 4}
-
-	// Create a Circle
-	circle := BasicCircle{Radius: 3}
+	// Every kind of shape, all used through the same BasicShape interface
+	for _, s := range shapes {
+		fmt.Printf("%s:\n", shapeName(s))
+		printShapeInfo(s)
+		fmt.Println()
+	}
 
-	fmt.Println("Rectangle:")
-	printShapeInfo(rect)
+	// Discover the optional capabilities with type assertions
+	fmt.Println("Optional capabilities, discovered with type assertions:")
+	yesNo := map[bool]string{true: "yes", false: "-"}
+	fmt.Printf("  %-10s %-7s %-10s %s\n", "Shape", "Scaler", "Translator", "Bounder")
+	for _, s := range shapes {
+		_, scales := s.(BasicScaler)
+		_, moves := s.(BasicTranslator)
+		_, bounded := s.(BasicBounder)
+		fmt.Printf("  %-10s %-7s %-10s %s\n", shapeName(s), yesNo[scales], yesNo[moves], yesNo[bounded])
+	}
 
-	fmt.Println("\nCircle:")
-	printShapeInfo(circle)
+	// Use a capability when it is there, and do without it otherwise
+	fmt.Println("\nEach shape doubled in size and moved up by 10, where it can be:")
+	for _, s := range shapes {
+		changed := s
+		if scaler, ok := changed.(BasicScaler); ok {
+			changed = scaler.Scale(2)
+		}
+		if translator, ok := changed.(BasicTranslator); ok {
+			changed = translator.Translate(0, 10)
+		}
+		if b, ok := changed.(BasicBounder); ok {
+			minX, minY, maxX, maxY := b.Bounds()
+			fmt.Printf("  %-10s area %6.2f → %6.2f, now within (%.1f, %.1f)-(%.1f, %.1f)\n",
+				shapeName(s), s.Area(), changed.Area(), minX, minY, maxX, maxY)
+		} else {
+			fmt.Printf("  %-10s area %6.2f, unchanged: it has none of the capabilities\n", shapeName(s), s.Area())
+		}
+	}
 
 	utils.PrintKey(`
 KEY TAKEAWAYS:
-- Both Rectangle and Circle implement the Shape interface by providing Area() and Perimeter() methods
+- Rectangle, Circle, Triangle and Square implement the Shape interface by providing Area() and Perimeter() methods
 - No explicit declaration is needed to say a type implements an interface
 - The PrintShapeInfo function can accept any type that satisfies the Shape interface
 - This allows for polymorphic behavior - different types responding to the same method calls
+- Optional behavior, such as Scaler, lives in its own small interface; a type assertion
+  such as s.(Scaler) tells whether a particular shape has it, and Square shows that
+  code keeps working with shapes that have none
+- A call through an interface is an indirect call that cannot be inlined; it costs a few
+  nanoseconds, which the Benchmark Lab measures against a direct call
 `)
 }
+
+// shapeName returns the name a shape has in the lesson's code, such as
+// Circle for a BasicCircle
+func shapeName(s BasicShape) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", s), "examples.Basic")
+}
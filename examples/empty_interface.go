@@ -169,6 +169,8 @@ KEY TAKEAWAYS:
 - It's useful for generic functions like PrintAny or data structures like Stack
 - To work with values stored in an empty interface, you need type assertions or reflection
 - In Go 1.18+, generics offer a more type-safe alternative for many use cases
+- Storing an int in interface{} allocates it on the heap; the Benchmark Lab compares the
+  Stack above with a typed and a generic stack
 - Empty interfaces sacrifice compile-time type checking for flexibility
 - Common uses: fmt package functions, containers/collections, plugins, and configuration
 `)
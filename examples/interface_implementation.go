@@ -2,8 +2,11 @@ package examples
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"go-interface-enum-explorer/pipeline"
 	"go-interface-enum-explorer/utils"
 )
 
@@ -12,27 +15,6 @@ type ImplWriter interface {
 	Write(data string) (int, error)
 }
 
-// Define ConsoleWriter for the InterfaceImplementation example
-type ImplConsoleWriter struct {
-	Prefix string
-}
-
-func (cw ImplConsoleWriter) Write(data string) (int, error) {
-	formatted := cw.Prefix + data
-	fmt.Println(formatted)
-	return len(formatted), nil
-}
-
-// Define FileLogger for the InterfaceImplementation example
-type ImplFileLogger struct {
-	FileName string
-}
-
-func (fl ImplFileLogger) Write(data string) (int, error) {
-	fmt.Printf("[Writing to %s]: %s\n", fl.FileName, data)
-	return len(data), nil
-}
-
 // Define UppercaseWriter for the InterfaceImplementation example
 type ImplUppercaseWriter struct {
 	ActualWriter ImplWriter
@@ -52,6 +34,11 @@ In Go, a type implements an interface by implementing its methods.
 There's no explicit declaration of intent like "implements" in other languages.
 This is called "implicit implementation" and is a key feature of Go's design.
 
+The code below declares its own Writer interface and then uses writers from
+the explorer's pipeline package, which was written without knowing about
+it: one that prints to the console and a log file that rotates when it gets
+too big. They have the right Write method, so they are Writers.
+
 Key points:
 - Any type that implements all methods of an interface automatically satisfies that interface
 - Interface implementation is implicit (no "implements" keyword)
@@ -65,64 +52,64 @@ type Writer interface {
         Write(data string) (int, error)
 }
 
-// ConsoleWriter writes data to the console
-type ConsoleWriter struct {
-        Prefix string
-}
-
-// Write method for ConsoleWriter - implements the Writer interface
-func (cw ConsoleWriter) Write(data string) (int, error) {
-        formatted := cw.Prefix + data
-        fmt.Println(formatted)
-        return len(formatted), nil
-}
-
-// FileLogger simulates logging to a file
-type FileLogger struct {
-        FileName string
-}
-
-// Write method for FileLogger - also implements the Writer interface
-func (fl FileLogger) Write(data string) (int, error) {
-        // In a real implementation, this would write to a file
-        fmt.Printf("[Writing to %s]: %s\n", fl.FileName, data)
-        return len(data), nil
-}
-
 // UppercaseWriter converts text to uppercase before writing
 type UppercaseWriter struct {
-        ActualWriter Writer // Composition with another Writer
+        ActualWriter Writer // Composition with another Writer <3>
 }
 
-// Write method for UppercaseWriter - also implements Writer interface
+// Write method for UppercaseWriter - implements the Writer interface
 func (uw UppercaseWriter) Write(data string) (int, error) {
         // Convert to uppercase and delegate to the embedded Writer
         return uw.ActualWriter.Write(strings.ToUpper(data))
 }
 
 // WriteToSomewhere is a function that uses the Writer interface
-func WriteToSomewhere(writer Writer, messages []string) {
+func WriteToSomewhere(writer Writer, messages []string) { // <4>
         for _, msg := range messages {
                 writer.Write(msg)
         }
 }
 
 func main() {
-        // Create instances of different Writers
-        console := ConsoleWriter{Prefix: "LOG: "}
-        file := FileLogger{FileName: "app.log"}
-        
+        // The writers of the pipeline package have the same Write method, so
+        // they are Writers too, although that package has never heard of
+        // this interface. This one writes to standard output <1>
+        console := pipeline.Chain(pipeline.FromIO(os.Stdout), pipeline.Prefix("LOG: "))
+
+        // A real log file, moved to app.log.1 when it would grow past 40
+        // bytes <2>
+        dir, err := os.MkdirTemp("", "logs")
+        if err != nil {
+                fmt.Println("Cannot create a directory for the log files:", err)
+                return
+        }
+        defer os.RemoveAll(dir)
+        file, err := pipeline.OpenRotating(filepath.Join(dir, "app.log"), 40, 1)
+        if err != nil {
+                fmt.Println("Cannot open the log file:", err)
+                return
+        }
+        defer file.Close()
+
         // Create a composed writer that converts to uppercase
         uppercaseConsole := UppercaseWriter{ActualWriter: console}
-        
-        messages := []string{"Hello, World!", "Learning Go interfaces", "Composition is powerful"}
-        
+
+        messages := []string{"Hello, World!\n", "Learning Go interfaces\n", "Composition is powerful\n"}
+
         fmt.Println("Writing to console:")
         WriteToSomewhere(console, messages)
-        
+
         fmt.Println("\nWriting to file:")
         WriteToSomewhere(file, messages)
-        
+        for _, name := range file.Files() {
+                data, err := os.ReadFile(name)
+                if err != nil {
+                        fmt.Println(err)
+                        continue
+                }
+                fmt.Printf("%s holds:\n%s", filepath.Base(name), data)
+        }
+
         fmt.Println("\nWriting uppercase to console:")
         WriteToSomewhere(uppercaseConsole, messages)
 }
@@ -130,38 +117,63 @@ func main() {
 
 	// Actual implementation
 	utils.PrintOutput("Running the code...")
+	implementationDemo()
 
-	// Function that uses the interface
+	utils.PrintKey(`
+KEY TAKEAWAYS:
+- The console writer <1> and the log file <2> of the pipeline package implement the Writer
+  interface by providing a Write method, without naming it anywhere
+- The UppercaseWriter shows interface composition by containing another Writer <3>
+- The WriteToSomewhere function <4> works with any type that satisfies the Writer interface
+- Different implementations of the same interface allow for different behaviors
+- This demonstrates the "program to an interface, not an implementation" principle
+- The log file is a real one: once it would grow past its size limit, the first two
+  messages move to app.log.1
+- The Writer Pipelines lesson builds a logging library out of writers like these
+`)
+}
+
+// implementationDemo runs the InterfaceImplementation code sample
+func implementationDemo() {
 	writeToSomewhere := func(writer ImplWriter, messages []string) {
 		for _, msg := range messages {
 			writer.Write(msg)
 		}
 	}
 
-	// Create instances of different Writers
-	console := ImplConsoleWriter{Prefix: "LOG: "}
-	file := ImplFileLogger{FileName: "app.log"}
+	console := pipeline.Chain(pipeline.FromIO(os.Stdout), pipeline.Prefix("LOG: "))
+
+	dir, err := os.MkdirTemp("", "explorer-logs")
+	if err != nil {
+		fmt.Println("Cannot create a directory for the log files:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	file, err := pipeline.OpenRotating(filepath.Join(dir, "app.log"), 40, 1)
+	if err != nil {
+		fmt.Println("Cannot open the log file:", err)
+		return
+	}
+	defer file.Close()
 
-	// Create a composed writer that converts to uppercase
 	uppercaseConsole := ImplUppercaseWriter{ActualWriter: console}
 
-	messages := []string{"Hello, World!", "Learning Go interfaces", "Composition is powerful"}
+	messages := []string{"Hello, World!\n", "Learning Go interfaces\n", "Composition is powerful\n"}
 
 	fmt.Println("Writing to console:")
 	writeToSomewhere(console, messages)
 
 	fmt.Println("\nWriting to file:")
 	writeToSomewhere(file, messages)
+	for _, name := range file.Files() {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("%s holds:\n%s", filepath.Base(name), data)
+	}
 
 	fmt.Println("\nWriting uppercase to console:")
 	writeToSomewhere(uppercaseConsole, messages)
-
-	utils.PrintKey(`
-KEY TAKEAWAYS:
-- Both ConsoleWriter and FileLogger implement the Writer interface by providing a Write method
-- The UppercaseWriter shows interface composition by containing another Writer
-- The WriteToSomewhere function works with any type that satisfies the Writer interface
-- Different implementations of the same interface allow for different behaviors
-- This demonstrates the "program to an interface, not an implementation" principle
-`)
 }
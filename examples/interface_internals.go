@@ -0,0 +1,213 @@
+package examples
+
+import (
+	"fmt"
+
+	"go-interface-enum-explorer/inspect"
+	"go-interface-enum-explorer/utils"
+)
+
+// InterfaceInternals shows what an interface value holds in memory and why
+// an interface holding a nil pointer is not nil
+func InterfaceInternals() {
+	utils.PrintExplanation(`
+INTERFACE VALUES UNDER THE HOOD
+=============================
+
+An interface value is two machine words. The first says what type of value
+is stored, the second where the value is:
+
+- For interface{} the first word points to the dynamic type's descriptor
+  (an "eface").
+- For an interface with methods, such as Animal, it points to an itab: a
+  table for one interface and one dynamic type, holding the type descriptor
+  and the addresses of the methods (an "iface"). Calling a method looks it
+  up there.
+- The second word holds the value itself when it is a single pointer (a
+  pointer, map, channel or function). Any other value is copied and the
+  word points to the copy - which is why storing a struct or an int in an
+  interface can allocate.
+
+An interface is nil only when both words are nil. Storing a nil pointer
+sets the type word, so the interface is no longer nil: the "typed nil"
+that makes err != nil true for a function that returned a nil *MyError.
+
+Key points:
+- Type assertions and type switches compare the type word, not the value
+- The itab is shared by all values of the same dynamic type in the same interface
+- Only pointer-shaped values fit in the data word; others are copied
+- A nil interface has no type; an interface holding a nil pointer does
+`)
+
+	utils.PrintCode(`
+type Animal interface {
+        Speak() string
+}
+
+type Dog struct {
+        Breed string
+}
+
+func (d Dog) Speak() string {
+        return "Woof!"
+}
+
+// words is how the gc compiler lays out an interface value: a type word
+// (an itab for interfaces with methods) and a data word
+type words struct {
+        typ  unsafe.Pointer
+        data unsafe.Pointer
+}
+
+func inspect(name string, a *Animal) {
+        w := (*words)(unsafe.Pointer(a))
+        fmt.Printf("%-10s type=%p data=%p dynamic=%T nil=%t\n", name, w.typ, w.data, *a, *a == nil)
+}
+
+// findDog returns a nil *Dog when there is no dog - in an Animal
+func findDog(found bool) Animal {
+        var d *Dog
+        if found {
+                d = &Dog{Breed: "Beagle"}
+        }
+        return d
+}
+
+func main() {
+        var a Animal
+        inspect("nil", &a) // both words are nil
+
+        a = Dog{Breed: "Poodle"}
+        inspect("value", &a) // data points to a copy of the Dog
+
+        dog := &Dog{Breed: "Collie"}
+        a = dog
+        inspect("pointer", &a) // data is the pointer itself
+
+        a = findDog(false)
+        inspect("typed nil", &a) // a type, no value: a != nil
+        if a != nil {
+                fmt.Println("findDog(false) != nil, although it returned a nil pointer")
+        }
+}
+`)
+
+	utils.PrintOutput("Running the code...")
+
+	if err := inspect.Verify(); err != nil {
+		fmt.Println("The words of interface values cannot be shown here:", err)
+		fmt.Println("Everything reflect can tell is still shown.")
+	}
+
+	// Values stored in AssertAnimal, an interface with methods
+	var a AssertAnimal = AssertDog{Breed: "Poodle"}
+	dogInAnimal := showInterface("var a AssertAnimal = AssertDog{Breed: \"Poodle\"}", &a)
+
+	dog := &AssertDog{Breed: "Collie"}
+	a = dog
+	showInterface("a = &AssertDog{Breed: \"Collie\"}", &a)
+	fmt.Printf("  (dog is %p: the data word is the pointer itself)\n", dog)
+
+	a = AssertCat{Color: "Black"}
+	catInAnimal := showInterface("a = AssertCat{Color: \"Black\"}", &a)
+
+	// The same values in interface{}
+	var v interface{} = AssertDog{Breed: "Poodle"}
+	dogInEmpty := showInterface("var v interface{} = AssertDog{Breed: \"Poodle\"}", &v)
+
+	v = 42
+	showInterface("v = 42", &v)
+
+	v = map[string]int{"legs": 4}
+	showInterface("v = map[string]int{\"legs\": 4}", &v)
+
+	if dogInAnimal.Words {
+		fmt.Println("\nComparing the type words:")
+		fmt.Printf("  AssertDog and AssertCat in AssertAnimal have different itabs: %t\n", dogInAnimal.TypeWord != catInAnimal.TypeWord)
+		fmt.Printf("  AssertDog has one type descriptor, in AssertAnimal (through the itab) and in interface{}: %t\n",
+			dogInAnimal.TypeDescriptor == dogInEmpty.TypeWord)
+	}
+
+	// Nil interface vs typed nil
+	fmt.Println("\nNil interface vs typed nil:")
+	var none AssertAnimal
+	showInterface("var none AssertAnimal", &none)
+
+	var nilDog *AssertDog
+	var typedNil AssertAnimal = nilDog
+	showInterface("var typedNil AssertAnimal = (*AssertDog)(nil)", &typedNil)
+
+	findDog := func(found bool) AssertAnimal {
+		var d *AssertDog
+		if found {
+			d = &AssertDog{Breed: "Beagle"}
+		}
+		return d
+	}
+	if found := findDog(false); found != nil {
+		fmt.Println("\nfindDog(false) != nil is true, although findDog returned a nil *AssertDog:")
+		fmt.Printf("  found holds type %T and value %v\n", found, found)
+	}
+
+	utils.PrintKey(`
+KEY TAKEAWAYS:
+- An interface value is a type word and a data word
+- For interfaces with methods the type word points to an itab, which also holds the methods
+- Pointer-shaped values are stored in the data word; other values are copied and pointed to
+- An interface is nil only when it holds no type at all
+- Returning a nil pointer as an interface gives a non-nil interface (a "typed nil");
+  return a literal nil instead when there is nothing to return
+`)
+}
+
+// showInterface prints what the interface variable p points to holds
+func showInterface(decl string, p interface{}) inspect.Value {
+	v, err := inspect.Inspect(p)
+	if err != nil {
+		fmt.Println(err)
+		return v
+	}
+
+	fmt.Println("\n" + decl)
+	kind := "interface with methods: itab + data"
+	if v.Empty {
+		kind = "empty interface: type + data"
+	}
+	fmt.Printf("  static type   %s (%s)\n", v.Static, kind)
+	if v.Nil {
+		fmt.Println("  dynamic type  none")
+	} else {
+		fmt.Printf("  dynamic type  %s\n", v.Dynamic)
+	}
+
+	if v.Words {
+		switch {
+		case v.Nil:
+			fmt.Printf("  type word     %#x\n", v.TypeWord)
+		case v.Empty:
+			fmt.Printf("  type word     %#x (type descriptor)\n", v.TypeWord)
+		default:
+			fmt.Printf("  itab          %#x → type descriptor %#x\n", v.TypeWord, v.TypeDescriptor)
+		}
+		switch {
+		case v.Nil:
+			fmt.Printf("  data word     %#x\n", v.DataWord)
+		case v.Indirect:
+			fmt.Printf("  data word     %#x → a copy of %s\n", v.DataWord, v.Value)
+		default:
+			fmt.Printf("  data word     %#x = %s (stored directly)\n", v.DataWord, v.Value)
+		}
+	} else if !v.Nil {
+		fmt.Printf("  value         %s (stored indirectly: %t)\n", v.Value, v.Indirect)
+	}
+
+	switch {
+	case v.Nil:
+		fmt.Println("  == nil        true: no type and no value")
+	case v.NilPointer:
+		fmt.Println("  == nil        false: it holds a type, even though the pointer is nil (typed nil)")
+	default:
+		fmt.Println("  == nil        false")
+	}
+	return v
+}
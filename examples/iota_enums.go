@@ -29,7 +29,7 @@ type Weekday int
 
 // Define the days of the week using iota
 const (
-        Sunday Weekday = iota    // 0
+        Sunday Weekday = iota    // 0 <1>
         Monday                   // 1
         Tuesday                  // 2
         Wednesday                // 3
@@ -43,7 +43,7 @@ type LogLevel int
 
 // Define log levels using iota with expressions
 const (
-        LogDebug LogLevel = iota * 10    // 0
+        LogDebug LogLevel = iota * 10    // 0 <2>
         LogInfo                          // 10
         LogWarning                       // 20
         LogError                         // 30
@@ -55,7 +55,7 @@ type BitFlag uint
 
 // Define bit flags using shifts with iota
 const (
-        ReadPermission BitFlag = 1 << iota  // 1 (001)
+        ReadPermission BitFlag = 1 << iota  // 1 (001) <3>
         WritePermission                      // 2 (010)
         ExecutePermission                    // 4 (100)
 )
@@ -194,9 +194,9 @@ KEY TAKEAWAYS:
 - Type safety is improved by using custom types for enums
 - iota can be combined with expressions for more complex sequences
 - Common patterns include:
-  - Simple incrementing values (0, 1, 2, ...)
-  - Multiplied values for levels or priorities (0, 10, 20, ...)
-  - Bit shifts for flags and masks (1, 2, 4, 8, ...)
+  - Simple incrementing values (0, 1, 2, ...) <1>
+  - Multiplied values for levels or priorities (0, 10, 20, ...) <2>
+  - Bit shifts for flags and masks (1, 2, 4, 8, ...) <3>
 - iota resets to 0 in each const block
 - Values can be skipped or customized as needed
 `)
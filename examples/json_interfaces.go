@@ -0,0 +1,276 @@
+package examples
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-interface-enum-explorer/codec"
+	"go-interface-enum-explorer/utils"
+)
+
+// shapesFile is the JSON file the JSONInterfaces lesson loads its shapes
+// from
+//
+//go:embed data/shapes.json
+var shapesFile []byte
+
+// ShapeCodec encodes and decodes BasicShape values with a "type" field
+var ShapeCodec = codec.NewRegistry[BasicShape]("shape").
+	MustRegister("rectangle", BasicRectangle{}).
+	MustRegister("circle", BasicCircle{}).
+	MustRegister("triangle", BasicTriangle{}).
+	MustRegister("regular-polygon", BasicRegularPolygon{}).
+	MustRegister("polygon", BasicPolygon{}).
+	MustRegister("square", BasicSquare{})
+
+// lessonShapeCodec is the registry of the JSONInterfaces code sample, which
+// only has rectangles and circles
+var lessonShapeCodec = codec.NewRegistry[BasicShape]("shape").
+	MustRegister("rectangle", BasicRectangle{}).
+	MustRegister("circle", BasicCircle{})
+
+// animalCodec does the same for AssertAnimal values
+var animalCodec = codec.NewRegistry[AssertAnimal]("animal").
+	MustRegister("dog", AssertDog{}).
+	MustRegister("cat", AssertCat{}).
+	MustRegister("duck", AssertDuck{})
+
+// JSONInterfaces demonstrates storing interface values as JSON, with a
+// discriminator field that says which concrete type to decode
+func JSONInterfaces() {
+	utils.PrintExplanation(`
+SAVING AND LOADING INTERFACE VALUES AS JSON
+=========================================
+
+encoding/json can write a []Shape: it encodes whatever concrete value each
+interface holds. Reading it back is the problem. The JSON for a circle is
+just {"radius":3}, and json.Unmarshal cannot create a Shape, because it
+does not know which type should implement it.
+
+The usual answer is a discriminator: a field, such as "type", that names
+the concrete type, as in {"type":"circle","radius":3}. A registry maps each
+name to a type. Decoding reads the name first, makes an empty value of that
+type and decodes the rest of the object into it.
+
+A registry is also a safety net. Only the types registered in it can come
+out of a file, and a name it does not know is an error, not a silent nil.
+
+Key points:
+- json.Unmarshal cannot decode into an interface with methods on its own
+- A discriminator field records which concrete type each value has
+- A registry maps discriminators to types, in both directions
+- Reject unknown discriminators and unknown fields with clear errors
+`)
+
+	utils.PrintCode(`
+type Shape interface {
+        Area() float64
+        Perimeter() float64
+}
+
+type Rectangle struct {
+        X      float64 ` + "`json:\"x,omitempty\"`" + `
+        Y      float64 ` + "`json:\"y,omitempty\"`" + `
+        Width  float64 ` + "`json:\"width\"`" + `
+        Height float64 ` + "`json:\"height\"`" + `
+}
+
+type Circle struct {
+        X      float64 ` + "`json:\"x,omitempty\"`" + `
+        Y      float64 ` + "`json:\"y,omitempty\"`" + `
+        Radius float64 ` + "`json:\"radius\"`" + `
+}
+
+func (r Rectangle) Area() float64      { return r.Width * r.Height }
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+func (c Circle) Area() float64         { return math.Pi * c.Radius * c.Radius }
+func (c Circle) Perimeter() float64    { return 2 * math.Pi * c.Radius }
+
+type Animal interface {
+        Speak() string
+}
+
+type Dog struct {
+        Breed string ` + "`json:\"breed\"`" + `
+}
+
+type Cat struct {
+        Color string ` + "`json:\"color\"`" + `
+}
+
+type Duck struct {
+        Habitat string ` + "`json:\"habitat\"`" + `
+}
+
+func (Dog) Speak() string  { return "Woof!" }
+func (Cat) Speak() string  { return "Meow!" }
+func (Duck) Speak() string { return "Quack!" }
+
+// The registries of the codec package map each name in the "type" field
+// to a concrete type, and back <1>
+var shapeCodec = codec.NewRegistry[Shape]("shape").
+        MustRegister("rectangle", Rectangle{}).
+        MustRegister("circle", Circle{})
+
+var animalCodec = codec.NewRegistry[Animal]("animal").
+        MustRegister("dog", Dog{}).
+        MustRegister("cat", Cat{}).
+        MustRegister("duck", Duck{})
+
+// shapes.json holds
+//
+//      [
+//        {"type": "rectangle", "width": 5, "height": 4},
+//        {"type": "circle", "radius": 3},
+//        {"type": "rectangle", "width": 2.5, "height": 2.5}
+//      ]
+//
+//go:embed shapes.json
+var shapesFile []byte
+
+func printShapeInfo(s Shape) {
+        fmt.Printf("Area: %.2f\n", s.Area())
+        fmt.Printf("Perimeter: %.2f\n", s.Perimeter())
+}
+
+// name returns the name of the concrete type of v, without "main."
+func name(v interface{}) string {
+        return strings.TrimPrefix(fmt.Sprintf("%T", v), "main.")
+}
+
+func main() {
+        fmt.Println("shapes.json:")
+        fmt.Println(strings.TrimSpace(string(shapesFile)))
+
+        // Each object becomes the type its "type" field names <2>
+        shapes, err := shapeCodec.UnmarshalSlice(shapesFile)
+        if err != nil {
+                fmt.Println("Could not load the shapes:", err)
+                return
+        }
+        for i, s := range shapes {
+                fmt.Printf("\nShape %d (%s):\n", i, name(s))
+                printShapeInfo(s)
+        }
+
+        // Encoding writes the discriminator first <3>
+        fmt.Println("\nThe shapes encoded again:")
+        encoded, err := shapeCodec.MarshalIndent(shapes, "", "  ")
+        if err != nil {
+                fmt.Println(err)
+        } else {
+                fmt.Println(string(encoded))
+        }
+
+        // The same registry type works for any interface
+        animals := []Animal{Dog{Breed: "Labrador"}, Cat{Color: "Black"}, Duck{Habitat: "Pond"}}
+        data, err := animalCodec.MarshalSlice(animals)
+        if err != nil {
+                fmt.Println(err)
+                return
+        }
+        fmt.Println("\nA []Animal as JSON:")
+        fmt.Println(string(data))
+        decoded, err := animalCodec.UnmarshalSlice(data)
+        if err != nil {
+                fmt.Println(err)
+                return
+        }
+        fmt.Println("Decoded again:")
+        for _, a := range decoded {
+                fmt.Printf("  %s says %s\n", name(a), a.Speak())
+        }
+
+        // What the registry rejects <4>
+        fmt.Println("\nInput the registry rejects:")
+        for _, input := range []string{
+                ` + "`" + `[{"type": "hexagon", "side": 2}]` + "`" + `,
+                ` + "`" + `[{"radius": 3}]` + "`" + `,
+                ` + "`" + `[{"type": "circle", "radius": 3}, {"type": "circle", "radius": 3, "colour": "red"}]` + "`" + `,
+        } {
+                _, err := shapeCodec.UnmarshalSlice([]byte(input))
+                fmt.Printf("  %s\n    %v\n", input, err)
+                var unknown *codec.UnknownTypeError
+                if errors.As(err, &unknown) && !unknown.Missing {
+                        fmt.Printf("    (errors.As finds the unknown name: %q)\n", unknown.Name)
+                }
+        }
+}
+`)
+
+	utils.PrintOutput("Running the code...")
+
+	fmt.Println("shapes.json:")
+	fmt.Println(strings.TrimSpace(string(shapesFile)))
+
+	shapes, err := lessonShapeCodec.UnmarshalSlice(shapesFile)
+	if err != nil {
+		fmt.Println("Could not load the shapes:", err)
+		return
+	}
+	for i, s := range shapes {
+		fmt.Printf("\nShape %d (%s):\n", i, sampleName(s))
+		printShapeInfo(s)
+	}
+
+	fmt.Println("\nThe shapes encoded again:")
+	encoded, err := lessonShapeCodec.MarshalIndent(shapes, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println(string(encoded))
+	}
+
+	animals := []AssertAnimal{AssertDog{Breed: "Labrador"}, AssertCat{Color: "Black"}, AssertDuck{Habitat: "Pond"}}
+	data, err := animalCodec.MarshalSlice(animals)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("\nA []Animal as JSON:")
+	fmt.Println(string(data))
+	decoded, err := animalCodec.UnmarshalSlice(data)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("Decoded again:")
+	for _, a := range decoded {
+		fmt.Printf("  %s says %s\n", sampleName(a), a.Speak())
+	}
+
+	fmt.Println("\nInput the registry rejects:")
+	for _, input := range []string{
+		`[{"type": "hexagon", "side": 2}]`,
+		`[{"radius": 3}]`,
+		`[{"type": "circle", "radius": 3}, {"type": "circle", "radius": 3, "colour": "red"}]`,
+	} {
+		_, err := lessonShapeCodec.UnmarshalSlice([]byte(input))
+		fmt.Printf("  %s\n    %v\n", input, err)
+		var unknown *codec.UnknownTypeError
+		if errors.As(err, &unknown) && !unknown.Missing {
+			fmt.Printf("    (errors.As finds the unknown name: %q)\n", unknown.Name)
+		}
+	}
+
+	utils.PrintKey(`
+KEY TAKEAWAYS:
+- json.Unmarshal needs a concrete type; an interface alone does not say which one
+- A discriminator field such as "type" stores the concrete type next to the value
+- A registry <1> maps names to types for decoding <2>, and types to names for encoding <3>
+- Unknown names, missing names and unexpected fields should fail loudly <4>
+- Only registered types can come out of the file, which keeps input under control
+`)
+}
+
+// sampleName returns the name the type of v has in the JSONInterfaces code
+// sample, such as Circle for examples.BasicCircle
+func sampleName(v interface{}) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", v), "examples.")
+	for _, prefix := range []string{"Basic", "Assert"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
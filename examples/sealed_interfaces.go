@@ -0,0 +1,150 @@
+package examples
+
+import (
+	"fmt"
+
+	"go-interface-enum-explorer/checks"
+	"go-interface-enum-explorer/utils"
+)
+
+// sealedCode is the code sample of the SealedInterfaces lesson. The lesson
+// runs the sealed-switch check on it, so it is kept in one place.
+const sealedCode = `
+// Animal is sealed: isAnimal is unexported, so only this package can
+// add animals, and it knows all of them
+type Animal interface {
+        Speak() string
+        isAnimal()
+}
+
+type Dog struct{ Breed string }
+type Cat struct{ Color string }
+type Duck struct{ Habitat string }
+
+func (Dog) Speak() string  { return "Woof!" }
+func (Cat) Speak() string  { return "Meow!" }
+func (Duck) Speak() string { return "Quack!" }
+
+func (Dog) isAnimal()  {}
+func (Cat) isAnimal()  {}
+func (Duck) isAnimal() {}
+
+// describe forgot the Duck, and the default case hides it
+func describe(a Animal) string {
+        switch v := a.(type) {
+        case Dog:
+                return "a " + v.Breed + " dog"
+        case Cat:
+                return "a " + v.Color + " cat"
+        default:
+                return "an unknown animal"
+        }
+}
+
+// describeAll has a case for every animal. A new animal is reported by
+// the check, and panics here if it gets through anyway.
+func describeAll(a Animal) string {
+        switch v := a.(type) {
+        case Dog:
+                return "a " + v.Breed + " dog"
+        case Cat:
+                return "a " + v.Color + " cat"
+        case Duck:
+                return "a duck from the " + v.Habitat
+        }
+        panic(fmt.Sprintf("unexpected animal %T", a))
+}
+
+func main() {
+        for _, a := range []Animal{Dog{"Labrador"}, Cat{"Black"}, Duck{"Pond"}} {
+                fmt.Printf("describe: %-20s describeAll: %s\n", describe(a), describeAll(a))
+        }
+}
+`
+
+// ClosedAnimal is the sealed version of AssertAnimal: the unexported
+// closedAnimal method means only this package can add animals to it
+type ClosedAnimal interface {
+	AssertAnimal
+	closedAnimal()
+}
+
+func (AssertDog) closedAnimal()  {}
+func (AssertCat) closedAnimal()  {}
+func (AssertDuck) closedAnimal() {}
+
+// describeClosed has a case for every ClosedAnimal and no default case, so
+// the sealed-switch check reports it when an animal is added
+func describeClosed(a ClosedAnimal) string {
+	switch v := a.(type) {
+	case AssertDog:
+		return fmt.Sprintf("a %s dog", v.Breed)
+	case AssertCat:
+		return fmt.Sprintf("a %s cat", v.Color)
+	case AssertDuck:
+		return fmt.Sprintf("a duck that lives in the %s", v.Habitat)
+	}
+	panic(fmt.Sprintf("unexpected ClosedAnimal %T", a))
+}
+
+// SealedInterfaces demonstrates closing an interface to other packages
+// with an unexported method, and checking type switches over it
+func SealedInterfaces() {
+	utils.PrintExplanation(`
+SEALED INTERFACES: A CLOSED FAMILY OF TYPES
+=========================================
+
+The describeAnimal type switch of the Type Assertion lesson ends with a
+default case. When someone adds a Parrot, the switch still compiles and the
+parrot is quietly described as "an unknown animal".
+
+Go has no keyword for a closed set of types, but an interface with an
+unexported method comes close. Only types in the same package can declare
+that method, so no other package can implement the interface: the package
+knows every implementation. Such an interface is called sealed.
+
+Because the family is known, a tool can check that a type switch over a
+sealed interface has a case for each member. The sealed-switch check (run
+with explorer check) reports every switch that misses one, and warns when a
+default case is what hides it.
+
+Key points:
+- An unexported marker method, such as isAnimal(), seals an interface
+- Every implementation lives in the interface's package
+- Type switches over a sealed interface can be checked for missing cases
+- Leave out the default case, or make it panic, so a missing case is noticed
+`)
+
+	utils.PrintCode(sealedCode)
+
+	utils.PrintOutput("Running the code...")
+
+	animals := []ClosedAnimal{
+		AssertDog{Breed: "Labrador"},
+		AssertCat{Color: "Black"},
+		AssertDuck{Habitat: "Pond"},
+	}
+	fmt.Println("Every ClosedAnimal, described by a switch with a case for each:")
+	for _, a := range animals {
+		fmt.Printf("  %-19T says %-7s %s\n", a, a.Speak(), describeClosed(a))
+	}
+
+	// A ClosedAnimal is still an AssertAnimal, so the open code keeps working
+	var open AssertAnimal = animals[0]
+	if closed, ok := open.(ClosedAnimal); ok {
+		fmt.Printf("\nAn AssertAnimal holding %T is a ClosedAnimal too: %s\n", open, describeClosed(closed))
+	}
+
+	// Run the sealed-switch check on the code sample above
+	fmt.Println("\nRunning the sealed-switch check on the code above:")
+	printFindings(checks.Snippet(sealedCode))
+
+	utils.PrintKey(`
+KEY TAKEAWAYS:
+- An interface with an unexported method is sealed: only its own package can implement it
+- A sealed interface can embed an open one, so values still work where the open one is expected
+- A default case in a type switch silently accepts types added later
+- explorer check reports type switches over sealed interfaces that miss an implementation
+- Prefer a default that panics, or none at all, over one that guesses
+`)
+}
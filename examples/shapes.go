@@ -0,0 +1,339 @@
+package examples
+
+import (
+	"fmt"
+	"math"
+
+	"go-interface-enum-explorer/codec"
+	"go-interface-enum-explorer/svg"
+)
+
+// The shapes below extend BasicShape beyond the rectangle and circle of the
+// BasicInterfaces example. Besides Area and Perimeter, most of them have
+// optional capabilities, described by small interfaces of their own, which
+// code discovers with a type assertion.
+
+// BasicScaler is a shape that can be resized. Scale returns a copy scaled
+// by factor around the shape's own reference point.
+type BasicScaler interface {
+	Scale(factor float64) BasicShape
+}
+
+// BasicTranslator is a shape that can be moved. Translate returns a copy
+// moved by dx, dy.
+type BasicTranslator interface {
+	Translate(dx, dy float64) BasicShape
+}
+
+// BasicBounder is a shape that knows the smallest upright rectangle
+// containing it. The method matches svg.Bounder, so every BasicBounder can
+// be placed in a drawing.
+type BasicBounder interface {
+	Bounds() (minX, minY, maxX, maxY float64)
+}
+
+// BasicPoint is a point in the plane, with y pointing up
+type BasicPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// distance returns the length of the segment from p to q
+func (p BasicPoint) distance(q BasicPoint) float64 {
+	return math.Hypot(q.X-p.X, q.Y-p.Y)
+}
+
+// BasicTriangle is a triangle given by its corners
+type BasicTriangle struct {
+	A BasicPoint `json:"a"`
+	B BasicPoint `json:"b"`
+	C BasicPoint `json:"c"`
+}
+
+// BasicRegularPolygon is a polygon with Sides equal sides, its corners on
+// a circle of the given Radius around X, Y. Its first corner points up.
+type BasicRegularPolygon struct {
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+	Sides  int     `json:"sides"`
+	Radius float64 `json:"radius"`
+}
+
+// BasicPolygon is any simple polygon, given by its corners in order
+type BasicPolygon struct {
+	Points []BasicPoint `json:"points"`
+}
+
+// BasicSquare is a shape written before the capabilities existed: it has
+// Area and Perimeter and nothing else, so it has no place in a drawing
+type BasicSquare struct {
+	Side float64 `json:"side"`
+}
+
+func (s BasicSquare) Area() float64      { return s.Side * s.Side }
+func (s BasicSquare) Perimeter() float64 { return 4 * s.Side }
+
+// shoelaceArea returns the area of the polygon through points with the
+// shoelace formula: half the absolute sum of the cross products of
+// neighboring corners
+func shoelaceArea(points []BasicPoint) float64 {
+	sum := 0.0
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		sum += p.X*q.Y - q.X*p.Y
+	}
+	return math.Abs(sum) / 2
+}
+
+// outline returns the perimeter of the closed polygon through points
+func outline(points []BasicPoint) float64 {
+	sum := 0.0
+	for i, p := range points {
+		sum += p.distance(points[(i+1)%len(points)])
+	}
+	return sum
+}
+
+// bounds returns the smallest upright rectangle containing points
+func bounds(points []BasicPoint) (minX, minY, maxX, maxY float64) {
+	if len(points) == 0 {
+		return 0, 0, 0, 0
+	}
+	minX, minY, maxX, maxY = points[0].X, points[0].Y, points[0].X, points[0].Y
+	for _, p := range points[1:] {
+		minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+		maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// polygonElement returns the SVG polygon through points
+func polygonElement(points []BasicPoint) string {
+	xy := make([]float64, 0, 2*len(points))
+	for _, p := range points {
+		xy = append(xy, p.X, p.Y)
+	}
+	return svg.Polygon(xy...)
+}
+
+// transform returns the points scaled by factor around origin and then
+// moved by dx, dy
+func transform(points []BasicPoint, origin BasicPoint, factor, dx, dy float64) []BasicPoint {
+	moved := make([]BasicPoint, len(points))
+	for i, p := range points {
+		moved[i] = BasicPoint{
+			X: origin.X + (p.X-origin.X)*factor + dx,
+			Y: origin.Y + (p.Y-origin.Y)*factor + dy,
+		}
+	}
+	return moved
+}
+
+// Rectangle capabilities; it scales around its lower left corner
+
+func (r BasicRectangle) Scale(factor float64) BasicShape {
+	r.Width *= factor
+	r.Height *= factor
+	return r
+}
+
+func (r BasicRectangle) Translate(dx, dy float64) BasicShape {
+	r.X += dx
+	r.Y += dy
+	return r
+}
+
+func (r BasicRectangle) Bounds() (minX, minY, maxX, maxY float64) {
+	return r.X, r.Y, r.X + r.Width, r.Y + r.Height
+}
+
+func (r BasicRectangle) SVGElement() string {
+	return svg.Rect(r.X, r.Y, r.Width, r.Height)
+}
+
+// Circle capabilities; it scales around its center
+
+func (c BasicCircle) Scale(factor float64) BasicShape {
+	c.Radius *= factor
+	return c
+}
+
+func (c BasicCircle) Translate(dx, dy float64) BasicShape {
+	c.X += dx
+	c.Y += dy
+	return c
+}
+
+func (c BasicCircle) Bounds() (minX, minY, maxX, maxY float64) {
+	return c.X - c.Radius, c.Y - c.Radius, c.X + c.Radius, c.Y + c.Radius
+}
+
+func (c BasicCircle) SVGElement() string {
+	return svg.Circle(c.X, c.Y, c.Radius)
+}
+
+// Triangle methods; it scales around its first corner
+
+func (t BasicTriangle) points() []BasicPoint { return []BasicPoint{t.A, t.B, t.C} }
+
+func (t BasicTriangle) Area() float64      { return shoelaceArea(t.points()) }
+func (t BasicTriangle) Perimeter() float64 { return outline(t.points()) }
+
+func (t BasicTriangle) Scale(factor float64) BasicShape {
+	p := transform(t.points(), t.A, factor, 0, 0)
+	return BasicTriangle{A: p[0], B: p[1], C: p[2]}
+}
+
+func (t BasicTriangle) Translate(dx, dy float64) BasicShape {
+	p := transform(t.points(), t.A, 1, dx, dy)
+	return BasicTriangle{A: p[0], B: p[1], C: p[2]}
+}
+
+func (t BasicTriangle) Bounds() (minX, minY, maxX, maxY float64) { return bounds(t.points()) }
+func (t BasicTriangle) SVGElement() string                       { return polygonElement(t.points()) }
+
+// Regular polygon methods; it scales around its center. Area and
+// Perimeter use the exact formulas rather than the corners. A polygon that
+// fails Validate has no corners, area or perimeter.
+
+// Validate rejects polygons with fewer than three sides or a negative
+// radius. ShapeCodec calls it for every polygon it decodes.
+func (p BasicRegularPolygon) Validate() error {
+	if p.Sides < 3 {
+		return fmt.Errorf("a regular polygon needs at least 3 sides, not %d", p.Sides)
+	}
+	if p.Radius < 0 {
+		return fmt.Errorf("the radius of a regular polygon cannot be negative, not %g", p.Radius)
+	}
+	return nil
+}
+
+// Vertices returns the corners of the polygon, counterclockwise from the
+// top
+func (p BasicRegularPolygon) Vertices() []BasicPoint {
+	if p.Validate() != nil {
+		return nil
+	}
+	points := make([]BasicPoint, p.Sides)
+	for i := range points {
+		angle := math.Pi/2 + 2*math.Pi*float64(i)/float64(p.Sides)
+		points[i] = BasicPoint{X: p.X + p.Radius*math.Cos(angle), Y: p.Y + p.Radius*math.Sin(angle)}
+	}
+	return points
+}
+
+func (p BasicRegularPolygon) Area() float64 {
+	if p.Validate() != nil {
+		return 0
+	}
+	n := float64(p.Sides)
+	return n / 2 * p.Radius * p.Radius * math.Sin(2*math.Pi/n)
+}
+
+func (p BasicRegularPolygon) Perimeter() float64 {
+	if p.Validate() != nil {
+		return 0
+	}
+	n := float64(p.Sides)
+	return 2 * n * p.Radius * math.Sin(math.Pi/n)
+}
+
+func (p BasicRegularPolygon) Scale(factor float64) BasicShape {
+	p.Radius *= factor
+	return p
+}
+
+func (p BasicRegularPolygon) Translate(dx, dy float64) BasicShape {
+	p.X += dx
+	p.Y += dy
+	return p
+}
+
+func (p BasicRegularPolygon) Bounds() (minX, minY, maxX, maxY float64) { return bounds(p.Vertices()) }
+func (p BasicRegularPolygon) SVGElement() string                       { return polygonElement(p.Vertices()) }
+
+// Polygon methods; it scales around its first corner. Like a regular
+// polygon, a polygon that fails Validate has no area, perimeter or extent.
+
+// Validate rejects polygons with fewer than three corners. ShapeCodec calls
+// it for every polygon it decodes.
+func (p BasicPolygon) Validate() error {
+	if len(p.Points) < 3 {
+		return fmt.Errorf("a polygon needs at least 3 points, not %d", len(p.Points))
+	}
+	return nil
+}
+
+func (p BasicPolygon) Area() float64 {
+	if p.Validate() != nil {
+		return 0
+	}
+	return shoelaceArea(p.Points)
+}
+
+func (p BasicPolygon) Perimeter() float64 {
+	if p.Validate() != nil {
+		return 0
+	}
+	return outline(p.Points)
+}
+
+func (p BasicPolygon) Scale(factor float64) BasicShape {
+	if len(p.Points) == 0 {
+		return p
+	}
+	return BasicPolygon{Points: transform(p.Points, p.Points[0], factor, 0, 0)}
+}
+
+func (p BasicPolygon) Translate(dx, dy float64) BasicShape {
+	return BasicPolygon{Points: transform(p.Points, BasicPoint{}, 1, dx, dy)}
+}
+
+func (p BasicPolygon) Bounds() (minX, minY, maxX, maxY float64) {
+	if p.Validate() != nil {
+		return 0, 0, 0, 0
+	}
+	return bounds(p.Points)
+}
+
+func (p BasicPolygon) SVGElement() string { return polygonElement(p.Points) }
+
+// The capabilities each shape promises, checked by the compiler
+var (
+	_ BasicScaler     = BasicRectangle{}
+	_ BasicTranslator = BasicRectangle{}
+	_ BasicBounder    = BasicRectangle{}
+	_ svg.Drawer      = BasicRectangle{}
+	_ BasicScaler     = BasicCircle{}
+	_ BasicTranslator = BasicCircle{}
+	_ BasicBounder    = BasicCircle{}
+	_ svg.Drawer      = BasicCircle{}
+	_ BasicScaler     = BasicTriangle{}
+	_ BasicTranslator = BasicTriangle{}
+	_ BasicBounder    = BasicTriangle{}
+	_ svg.Drawer      = BasicTriangle{}
+	_ BasicScaler     = BasicRegularPolygon{}
+	_ BasicTranslator = BasicRegularPolygon{}
+	_ BasicBounder    = BasicRegularPolygon{}
+	_ svg.Drawer      = BasicRegularPolygon{}
+	_ codec.Validator = BasicRegularPolygon{}
+	_ BasicScaler     = BasicPolygon{}
+	_ BasicTranslator = BasicPolygon{}
+	_ BasicBounder    = BasicPolygon{}
+	_ svg.Drawer      = BasicPolygon{}
+	_ codec.Validator = BasicPolygon{}
+	_ svg.Bounder     = BasicBounder(nil)
+)
+
+// ShapeGallery returns one shape of each kind, laid out side by side, for
+// explorer draw to draw when it is given no file
+func ShapeGallery() []BasicShape {
+	return []BasicShape{
+		BasicRectangle{Width: 5, Height: 4},
+		BasicCircle{X: 9, Y: 2, Radius: 3},
+		BasicTriangle{A: BasicPoint{X: 13, Y: 0}, B: BasicPoint{X: 17, Y: 0}, C: BasicPoint{X: 15, Y: 4}},
+		BasicRegularPolygon{X: 21, Y: 2, Sides: 6, Radius: 2},
+		BasicPolygon{Points: []BasicPoint{{X: 24, Y: 0}, {X: 28, Y: 0}, {X: 28, Y: 1}, {X: 25, Y: 1}, {X: 25, Y: 4}, {X: 24, Y: 4}}},
+		BasicSquare{Side: 2},
+	}
+}
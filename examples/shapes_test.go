@@ -0,0 +1,145 @@
+package examples
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"go-interface-enum-explorer/svg"
+)
+
+// near reports whether a and b are equal up to rounding
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// lShape is the L of the gallery, moved to the origin: a 4 by 1 bar with
+// a 1 by 3 bar on its left end
+var lShape = BasicPolygon{Points: []BasicPoint{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 4}, {X: 0, Y: 4}}}
+
+func TestShapeMeasures(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		shape           BasicShape
+		area, perimeter float64
+	}{
+		{"rectangle", BasicRectangle{X: 7, Y: -2, Width: 5, Height: 4}, 20, 18},
+		{"circle", BasicCircle{Radius: 3}, 9 * math.Pi, 6 * math.Pi},
+		{"square", BasicSquare{Side: 2}, 4, 8},
+		{"right triangle", BasicTriangle{A: BasicPoint{}, B: BasicPoint{X: 4}, C: BasicPoint{Y: 3}}, 6, 12},
+		{"clockwise triangle", BasicTriangle{A: BasicPoint{}, B: BasicPoint{Y: 3}, C: BasicPoint{X: 4}}, 6, 12},
+		{"regular square", BasicRegularPolygon{Sides: 4, Radius: math.Sqrt2}, 4, 8},
+		{"regular hexagon", BasicRegularPolygon{X: 5, Y: 5, Sides: 6, Radius: 2}, 6 * math.Sqrt(3), 12},
+		{"polygon", lShape, 7, 16},
+		{"regular polygon with two sides", BasicRegularPolygon{Sides: 2, Radius: 1}, 0, 0},
+		{"polygon with two points", BasicPolygon{Points: []BasicPoint{{}, {X: 1, Y: 1}}}, 0, 0},
+		{"empty polygon", BasicPolygon{}, 0, 0},
+	} {
+		if got := tc.shape.Area(); !near(got, tc.area) {
+			t.Errorf("%s: Area() = %g, want %g", tc.name, got, tc.area)
+		}
+		if got := tc.shape.Perimeter(); !near(got, tc.perimeter) {
+			t.Errorf("%s: Perimeter() = %g, want %g", tc.name, got, tc.perimeter)
+		}
+	}
+}
+
+func TestPolygonValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		shape BasicShape
+		err   string
+	}{
+		{"triangle", BasicPolygon{Points: lShape.Points[:3]}, ""},
+		{"two points", BasicPolygon{Points: lShape.Points[:2]}, "a polygon needs at least 3 points, not 2"},
+		{"no points", BasicPolygon{}, "a polygon needs at least 3 points, not 0"},
+		{"hexagon", BasicRegularPolygon{Sides: 6, Radius: 2}, ""},
+		{"two sides", BasicRegularPolygon{Sides: 2, Radius: 2}, "a regular polygon needs at least 3 sides, not 2"},
+		{"negative radius", BasicRegularPolygon{Sides: 3, Radius: -1}, "the radius of a regular polygon cannot be negative, not -1"},
+	} {
+		err := tc.shape.(interface{ Validate() error }).Validate()
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: Validate() = %v, want nil", tc.name, err)
+		case tc.err != "" && (err == nil || err.Error() != tc.err):
+			t.Errorf("%s: Validate() = %v, want %q", tc.name, err, tc.err)
+		}
+	}
+
+	// An invalid polygon has no extent, so it cannot stretch a drawing
+	if x0, y0, x1, y1 := (BasicPolygon{Points: lShape.Points[:2]}).Bounds(); x0 != 0 || y0 != 0 || x1 != 0 || y1 != 0 {
+		t.Errorf("Bounds() of a polygon with two points = %g, %g, %g, %g, want zeros", x0, y0, x1, y1)
+	}
+
+	// ShapeCodec rejects it when decoding
+	_, err := ShapeCodec.Unmarshal([]byte(`{"type": "polygon", "points": [{"x": 0, "y": 0}, {"x": 1, "y": 1}]}`))
+	if err == nil || !strings.Contains(err.Error(), "at least 3 points") {
+		t.Errorf("decoding a polygon with two points: err = %v, want the Validate error", err)
+	}
+}
+
+func TestShapeCapabilities(t *testing.T) {
+	for _, tc := range []struct {
+		name                                string
+		shape                               BasicShape
+		scaler, translator, bounder, drawer bool
+	}{
+		{"rectangle", BasicRectangle{Width: 1, Height: 1}, true, true, true, true},
+		{"circle", BasicCircle{Radius: 1}, true, true, true, true},
+		{"triangle", BasicTriangle{B: BasicPoint{X: 1}, C: BasicPoint{Y: 1}}, true, true, true, true},
+		{"regular polygon", BasicRegularPolygon{Sides: 5, Radius: 1}, true, true, true, true},
+		{"polygon", lShape, true, true, true, true},
+		{"square", BasicSquare{Side: 1}, false, false, false, false},
+	} {
+		_, scaler := tc.shape.(BasicScaler)
+		_, translator := tc.shape.(BasicTranslator)
+		_, bounder := tc.shape.(BasicBounder)
+		_, drawer := tc.shape.(svg.Drawer)
+		if scaler != tc.scaler || translator != tc.translator || bounder != tc.bounder || drawer != tc.drawer {
+			t.Errorf("%s: Scaler %t, Translator %t, Bounder %t, Drawer %t; want %t, %t, %t, %t",
+				tc.name, scaler, translator, bounder, drawer, tc.scaler, tc.translator, tc.bounder, tc.drawer)
+		}
+		if !tc.scaler {
+			continue
+		}
+
+		// Scaling by 2 quadruples the area and doubles the perimeter
+		scaled := tc.shape.(BasicScaler).Scale(2)
+		if !near(scaled.Area(), 4*tc.shape.Area()) || !near(scaled.Perimeter(), 2*tc.shape.Perimeter()) {
+			t.Errorf("%s: Scale(2) has area %g and perimeter %g, want %g and %g",
+				tc.name, scaled.Area(), scaled.Perimeter(), 4*tc.shape.Area(), 2*tc.shape.Perimeter())
+		}
+
+		// Translating keeps the measures and moves the bounds
+		moved := tc.shape.(BasicTranslator).Translate(3, -1)
+		if !near(moved.Area(), tc.shape.Area()) {
+			t.Errorf("%s: Translate changed the area from %g to %g", tc.name, tc.shape.Area(), moved.Area())
+		}
+		x0, y0, x1, y1 := tc.shape.(BasicBounder).Bounds()
+		mx0, my0, mx1, my1 := moved.(BasicBounder).Bounds()
+		if !near(mx0, x0+3) || !near(my0, y0-1) || !near(mx1, x1+3) || !near(my1, y1-1) {
+			t.Errorf("%s: Translate(3, -1) moved the bounds from %g, %g, %g, %g to %g, %g, %g, %g",
+				tc.name, x0, y0, x1, y1, mx0, my0, mx1, my1)
+		}
+	}
+}
+
+func TestShapeBounds(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		shape                  BasicBounder
+		minX, minY, maxX, maxY float64
+	}{
+		{"rectangle", BasicRectangle{X: 1, Y: 2, Width: 5, Height: 4}, 1, 2, 6, 6},
+		{"circle", BasicCircle{X: 9, Y: 2, Radius: 3}, 6, -1, 12, 5},
+		{"triangle", BasicTriangle{A: BasicPoint{X: 13}, B: BasicPoint{X: 17}, C: BasicPoint{X: 15, Y: 4}}, 13, 0, 17, 4},
+		{"regular square", BasicRegularPolygon{Sides: 4, Radius: 1}, -1, -1, 1, 1},
+		{"polygon", lShape, 0, 0, 4, 4},
+	} {
+		minX, minY, maxX, maxY := tc.shape.Bounds()
+		if !near(minX, tc.minX) || !near(minY, tc.minY) || !near(maxX, tc.maxX) || !near(maxY, tc.maxY) {
+			t.Errorf("%s: Bounds() = %g, %g, %g, %g, want %g, %g, %g, %g",
+				tc.name, minX, minY, maxX, maxY, tc.minX, tc.minY, tc.maxX, tc.maxY)
+		}
+	}
+}
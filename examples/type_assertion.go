@@ -13,7 +13,7 @@ type AssertAnimal interface {
 
 // Define Dog for the TypeAssertion example
 type AssertDog struct {
-	Breed string
+	Breed string `json:"breed"`
 }
 
 func (d AssertDog) Speak() string {
@@ -22,7 +22,7 @@ func (d AssertDog) Speak() string {
 
 // Define Cat for the TypeAssertion example
 type AssertCat struct {
-	Color string
+	Color string `json:"color"`
 }
 
 func (c AssertCat) Speak() string {
@@ -31,7 +31,7 @@ func (c AssertCat) Speak() string {
 
 // Define Duck for the TypeAssertion example
 type AssertDuck struct {
-	Habitat string
+	Habitat string `json:"habitat"`
 }
 
 func (d AssertDuck) Speak() string {
@@ -0,0 +1,195 @@
+package examples
+
+import (
+	"fmt"
+
+	"go-interface-enum-explorer/checks"
+	"go-interface-enum-explorer/utils"
+)
+
+// typedNilCode is the code sample of the TypedNil lesson. The lesson runs
+// the typed-nil check on it, so it is kept in one place.
+const typedNilCode = `
+type Closer interface {
+        Close() error
+}
+
+type FileHandler struct {
+        filename string
+        isOpen   bool
+}
+
+func (f *FileHandler) Close() error {
+        fmt.Println("Closing", f.filename)
+        f.isOpen = false
+        return nil
+}
+
+// openHandler seems to return no handler when there is no file name
+func openHandler(name string) Closer {
+        var h *FileHandler
+        if name != "" {
+                h = &FileHandler{filename: name, isOpen: true}
+        }
+        return h // a nil *FileHandler becomes a non-nil Closer
+}
+
+// openHandlerFixed returns a literal nil when there is no handler
+func openHandlerFixed(name string) Closer {
+        if name == "" {
+                return nil
+        }
+        return &FileHandler{filename: name, isOpen: true}
+}
+
+// HandlerError is an error type with a pointer receiver
+type HandlerError struct {
+        Name string
+}
+
+func (e *HandlerError) Error() string {
+        return "cannot open " + e.Name
+}
+
+// validate returns a *HandlerError as an error, even when there is none
+func validate(name string) error {
+        var err *HandlerError
+        if name == "" {
+                err = &HandlerError{Name: name}
+        }
+        return err
+}
+
+func main() {
+        if h := openHandler(""); h != nil {
+                fmt.Printf("got a handler: %T\n", h) // (*FileHandler)(nil)
+                // h.Close() panics: Close reads f.filename through a nil pointer
+        }
+        if h := openHandlerFixed(""); h == nil {
+                fmt.Println("no handler, as expected")
+        }
+        if err := validate("notes.txt"); err != nil {
+                fmt.Println("validation failed, although the name is fine")
+        }
+}
+`
+
+// TypedNil demonstrates how a nil pointer returned as an interface makes
+// the interface non-nil, and how the typed-nil check finds it
+func TypedNil() {
+	utils.PrintExplanation(`
+TYPED NIL: WHEN A NIL POINTER IS NOT A NIL INTERFACE
+==================================================
+
+An interface value holds a type and a value. It equals nil only when it
+holds neither. Returning a nil *CompFileHandler as a CompCloser gives the
+caller an interface that holds the type *CompFileHandler and a nil value,
+so h != nil is true, and calling a method on it may panic.
+
+The same happens with errors: a function that declares var err *MyError
+and returns it as an error always returns a non-nil error, even on success.
+
+Key points:
+- An interface is nil only when it has no dynamic type
+- Assigning a nil pointer to an interface gives it a type, so it is no longer nil
+- Return a literal nil for interface results when there is nothing to return
+- Declare error variables as error, not as a pointer to your error type
+- The typed-nil check (explorer check) finds these returns in a package
+`)
+
+	utils.PrintCode(typedNilCode)
+
+	utils.PrintOutput("Running the code...")
+
+	// openHandler returns a nil *CompFileHandler as a CompCloser when
+	// there is no file name
+	openHandler := func(name string) CompCloser {
+		var h *CompFileHandler
+		if name != "" {
+			h = &CompFileHandler{filename: name, isOpen: true}
+		}
+		return h
+	}
+	openHandlerFixed := func(name string) CompCloser {
+		if name == "" {
+			return nil
+		}
+		return &CompFileHandler{filename: name, isOpen: true}
+	}
+
+	fmt.Println("openHandler(\"notes.txt\"):")
+	if h := openHandler("notes.txt"); h != nil {
+		h.Close()
+	}
+
+	fmt.Println("\nopenHandler(\"\"):")
+	h := openHandler("")
+	fmt.Printf("h == nil: %t, dynamic type: %T, value: %v\n", h == nil, h, h)
+	if h != nil {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Println("h.Close() panicked:", r)
+				}
+			}()
+			h.Close()
+		}()
+	}
+
+	fmt.Println("\nopenHandlerFixed(\"\"):")
+	fixed := openHandlerFixed("")
+	fmt.Printf("fixed == nil: %t\n", fixed == nil)
+
+	// The same pitfall with an error
+	validate := func(name string) error {
+		var err *CompOpenError
+		if name == "" {
+			err = &CompOpenError{Name: name}
+		}
+		return err
+	}
+	err := validate("notes.txt")
+	fmt.Printf("\nvalidate(\"notes.txt\") == nil: %t (it holds %T)\n", err == nil, err)
+
+	// Run the typed-nil check on the code sample above
+	fmt.Println("\nRunning the typed-nil check on the code above:")
+	printFindings(checks.Snippet(typedNilCode))
+
+	utils.PrintKey(`
+KEY TAKEAWAYS:
+- An interface holding a nil pointer is not equal to nil: it has a dynamic type
+- Functions returning interfaces (including error) should return a literal nil
+  when there is nothing to return
+- Keep error variables of type error, not *MyError, until the moment you return them
+- Calling a method on such an interface may panic when the method uses its receiver
+- explorer check scans a package for returns where a nil pointer can flow into an interface
+`)
+}
+
+// CompOpenError is the error the TypedNil lesson returns for a file that
+// cannot be opened, with a pointer receiver like most custom errors
+type CompOpenError struct {
+	Name string
+}
+
+func (e *CompOpenError) Error() string {
+	return "cannot open " + e.Name
+}
+
+// printFindings prints what a check found in a lesson's code sample
+func printFindings(findings []checks.Finding, err error) {
+	if err != nil {
+		fmt.Println("The check could not run:", err)
+	}
+	for _, f := range findings {
+		fmt.Printf("\nline %d, in %s:\n  %s\n", f.Pos.Line, f.Func, f.Message)
+		for _, l := range f.Excerpt {
+			marker := " "
+			if l.Marked {
+				marker = ">"
+			}
+			fmt.Printf("  %s %3d | %s\n", marker, l.Number, l.Text)
+		}
+		fmt.Println("  Fix:", f.Fix)
+	}
+}
@@ -0,0 +1,299 @@
+package examples
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	"go-interface-enum-explorer/pipeline"
+	"go-interface-enum-explorer/utils"
+)
+
+// WriterPipelines builds a logging pipeline out of writers with the
+// ImplWriter method, using the pipeline package
+func WriterPipelines() {
+	utils.PrintExplanation(`
+WRITER PIPELINES
+================
+
+The Writer interface of the Interface Implementation lesson has a single
+method, and that is enough to build a small logging library. Sinks send data
+somewhere: a memory buffer, the console, or several writers at once.
+Middleware is a function that wraps one Writer in another, which changes the
+data on its way to the next one: it puts a prefix or a timestamp on every
+line, or hides passwords.
+
+Because every piece is a Writer, the pieces can be put together in any
+order, and a writer written for something else, such as the UppercaseWriter
+of the previous lesson, fits in without changes. Two small adapters turn a
+Writer into an io.Writer and back, so fmt.Fprintf and the log package can
+write to a pipeline too.
+
+The code below is a short version of the explorer's pipeline package, whose
+RotatingFile sink the Interface Implementation lesson writes its log file
+with. The explorer conform command checks that every
+writer of that package keeps the same contract: a write returns len(data),
+an empty write does nothing, and after Flush everything has reached the sink.
+
+Key points:
+- Small single-method interfaces are easy to implement and to combine
+- Middleware is a function from Writer to Writer
+- A writer that holds data back can offer Flush through a second interface
+- Adapters connect your interface to the standard library's io.Writer
+`)
+
+	utils.PrintCode(`
+// Writer is the interface of the Interface Implementation lesson
+type Writer interface {
+        Write(data string) (int, error)
+}
+
+// Flusher is a writer that holds data back until it is flushed
+type Flusher interface {
+        Flush() error
+}
+
+// Flush flushes w if it is a Flusher
+func Flush(w Writer) error {
+        if f, ok := w.(Flusher); ok { // <1>
+                return f.Flush()
+        }
+        return nil
+}
+
+// Middleware wraps a writer in another one that changes the data on its
+// way to the next writer
+type Middleware func(next Writer) Writer
+
+// Chain wraps sink in the middleware; data goes through them in order
+func Chain(sink Writer, middleware ...Middleware) Writer { // <2>
+        w := sink
+        for i := len(middleware) - 1; i >= 0; i-- {
+                w = middleware[i](w)
+        }
+        return w
+}
+
+// Buffer is a sink that keeps everything in memory
+type Buffer struct {
+        strings.Builder
+}
+
+func (b *Buffer) Write(data string) (int, error) {
+        return b.WriteString(data)
+}
+
+// Tee is a sink that copies everything to several writers
+type Tee []Writer
+
+func (t Tee) Write(data string) (int, error) {
+        for _, w := range t {
+                if _, err := w.Write(data); err != nil {
+                        return 0, err
+                }
+        }
+        return len(data), nil
+}
+
+// ioWriter turns an io.Writer, such as os.Stdout, into a Writer...
+type ioWriter struct {
+        w io.Writer
+}
+
+func (i ioWriter) Write(data string) (int, error) {
+        return io.WriteString(i.w, data)
+}
+
+// ...and toIO turns a Writer into an io.Writer, for fmt and log
+type toIO struct {
+        w Writer
+}
+
+func (t toIO) Write(p []byte) (int, error) { // <3>
+        if _, err := t.w.Write(string(p)); err != nil {
+                return 0, err
+        }
+        return len(p), nil
+}
+
+// linePrefixer puts a prefix at the start of every line, remembering
+// whether the last write ended in the middle of one
+type linePrefixer struct {
+        next    Writer
+        prefix  func() string
+        midLine bool
+}
+
+func (p *linePrefixer) Write(data string) (int, error) {
+        var b strings.Builder
+        for rest := data; rest != ""; {
+                if !p.midLine {
+                        b.WriteString(p.prefix())
+                }
+                i := strings.IndexByte(rest, '\n')
+                if i < 0 {
+                        b.WriteString(rest)
+                        p.midLine = true
+                        break
+                }
+                b.WriteString(rest[:i+1])
+                rest = rest[i+1:]
+                p.midLine = false
+        }
+        if _, err := p.next.Write(b.String()); err != nil {
+                return 0, err
+        }
+        return len(data), nil
+}
+
+func Prefix(prefix string) Middleware {
+        return func(next Writer) Writer {
+                return &linePrefixer{next: next, prefix: func() string { return prefix }}
+        }
+}
+
+func Timestamp(layout string, clock func() time.Time) Middleware {
+        return func(next Writer) Writer {
+                return &linePrefixer{next: next, prefix: func() string { return clock().Format(layout) + " " }}
+        }
+}
+
+// redactor replaces the first group of every match with [REDACTED]
+type redactor struct {
+        next    Writer
+        pattern *regexp.Regexp
+}
+
+func (r redactor) Write(data string) (int, error) {
+        var b strings.Builder
+        last := 0
+        for _, m := range r.pattern.FindAllStringSubmatchIndex(data, -1) {
+                b.WriteString(data[last:m[2]])
+                b.WriteString("[REDACTED]")
+                last = m[3]
+        }
+        b.WriteString(data[last:])
+        if _, err := r.next.Write(b.String()); err != nil {
+                return 0, err
+        }
+        return len(data), nil
+}
+
+func Redact(pattern *regexp.Regexp) Middleware {
+        return func(next Writer) Writer { return redactor{next, pattern} }
+}
+
+// lineBuffer passes on whole lines only, so the middleware after it
+// never sees half a secret
+type lineBuffer struct {
+        next    Writer
+        pending string
+}
+
+func (l *lineBuffer) Write(data string) (int, error) {
+        i := strings.LastIndexByte(data, '\n')
+        if i < 0 {
+                l.pending += data
+                return len(data), nil
+        }
+        lines := l.pending + data[:i+1]
+        l.pending = data[i+1:]
+        if _, err := l.next.Write(lines); err != nil {
+                return 0, err
+        }
+        return len(data), nil
+}
+
+// Flush passes on an unfinished last line
+func (l *lineBuffer) Flush() error {
+        if l.pending == "" {
+                return nil
+        }
+        _, err := l.next.Write(l.pending)
+        l.pending = ""
+        return err
+}
+
+func LineBuffering() Middleware {
+        return func(next Writer) Writer { return &lineBuffer{next: next} }
+}
+
+// UppercaseWriter is the writer of the Interface Implementation lesson
+type UppercaseWriter struct {
+        ActualWriter Writer
+}
+
+func (uw UppercaseWriter) Write(data string) (int, error) {
+        return uw.ActualWriter.Write(strings.ToUpper(data))
+}
+
+func main() {
+        var memory Buffer
+        // A fixed clock keeps the output the same on every run
+        clock := func() time.Time { return time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC) }
+        logger := Chain(
+                Tee{ioWriter{os.Stdout}, &memory},
+                LineBuffering(),
+                Redact(regexp.MustCompile(` + "`" + `password=(\S+)` + "`" + `)),
+                Timestamp("15:04:05", clock),
+                Prefix("LOG: "),
+        )
+
+        lines := []string{"Hello, World!\n", "user alice logged in with password=hunter2\n", "Learning Go ", "interfaces\n"}
+        for _, line := range lines {
+                logger.Write(line)
+        }
+        UppercaseWriter{ActualWriter: logger}.Write("composition is powerful\n") // <4>
+
+        // Through the adapter, fmt and the log package write to the pipeline
+        fmt.Fprintf(toIO{logger}, "%d messages so far\n", len(lines)+1)
+        log.New(toIO{logger}, "", 0).Println("written by log.Logger")
+        logger.Write("goodbye")
+        Flush(logger)
+
+        fmt.Printf("\n\nThe memory buffer got the same %d bytes\n", len(memory.String()))
+}
+`)
+
+	// Actual implementation
+	utils.PrintOutput("Running the code...")
+
+	var memory pipeline.Buffer
+	// A fixed clock keeps the output the same on every run
+	clock := func() time.Time { return time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC) }
+	logger := pipeline.Chain(
+		pipeline.Tee(pipeline.FromIO(os.Stdout), &memory),
+		pipeline.LineBuffering(),
+		pipeline.Redact(regexp.MustCompile(`password=(\S+)`)),
+		pipeline.Timestamp("15:04:05", clock),
+		pipeline.Prefix("LOG: "),
+	)
+
+	lines := []string{"Hello, World!\n", "user alice logged in with password=hunter2\n", "Learning Go ", "interfaces\n"}
+	for _, line := range lines {
+		logger.Write(line)
+	}
+	ImplUppercaseWriter{ActualWriter: logger}.Write("composition is powerful\n")
+
+	fmt.Fprintf(pipeline.ToIO(logger), "%d messages so far\n", len(lines)+1)
+	log.New(pipeline.ToIO(logger), "", 0).Println("written by log.Logger")
+	logger.Write("goodbye")
+	pipeline.Flush(logger)
+
+	fmt.Printf("\n\nThe memory buffer got the same %d bytes\n", len(memory.String()))
+
+	utils.PrintKey(`
+KEY TAKEAWAYS:
+- Flush <1> is an optional capability: writers that buffer implement Flusher, and a
+  type assertion finds them, as Scaler is found in the Basic Interfaces lesson
+- Chain <2> wraps the sink in each middleware in turn; data passes through them in
+  the order they are listed
+- The toIO adapter <3> lets fmt.Fprintf and log.Logger write to any Writer
+- The UppercaseWriter <4> was written before the pipeline existed and fits into it
+  because it has the same Write method
+- LineBuffering goes first so that Redact sees whole lines, even when a secret is
+  written in pieces
+`)
+}
@@ -0,0 +1,122 @@
+// Package explain recognizes the compiler and vet errors learners hit with
+// interfaces and enums, such as a method with a pointer receiver or a
+// constant of the wrong enum type, and explains them in plain language
+// with a suggested fix and the lesson that covers the mistake.
+package explain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic is one message from go build or go vet. Messages continued on
+// indented lines, such as the have/want lines of a wrong method signature,
+// are joined to the message with newlines. Pasted messages without a file
+// position have an empty File.
+type Diagnostic struct {
+	File         string
+	Line, Column int
+	Message      string
+}
+
+// Position returns "file:line:column", or "" when the position is unknown
+func (d Diagnostic) Position() string {
+	if d.File == "" {
+		return ""
+	}
+	pos := d.File + ":" + strconv.Itoa(d.Line)
+	if d.Column > 0 {
+		pos += ":" + strconv.Itoa(d.Column)
+	}
+	return pos
+}
+
+// positionPattern matches the start of a message, such as
+// "./main.go:12:5: " or, from go vet, "vet: main.go:12: "
+var positionPattern = regexp.MustCompile(`^(?:vet: )?(\S+?\.go):(\d+):(?:(\d+):)?\s*(.*)$`)
+
+// Parse splits the output of go build or go vet into diagnostics. Package
+// headers such as "# example", the "too many errors" notice, lines that only refer back to an earlier
+// position and messages reported twice, as when the output of go build and
+// go vet is pasted together, are left out.
+func Parse(output string) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := make(map[Diagnostic]bool)
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "# "):
+			continue
+		case line[0] == ' ' || line[0] == '\t':
+			// Continuation of the previous message, unless it only points
+			// at another position, such as the first of two duplicate cases
+			if positionPattern.MatchString(trimmed) {
+				continue
+			}
+			if len(diagnostics) > 0 {
+				last := &diagnostics[len(diagnostics)-1]
+				last.Message += "\n" + trimmed
+				continue
+			}
+		}
+		if m := positionPattern.FindStringSubmatch(trimmed); m != nil {
+			if m[4] == "too many errors" {
+				continue
+			}
+			d := Diagnostic{File: strings.TrimPrefix(m[1], "./"), Message: m[4]}
+			d.Line, _ = strconv.Atoi(m[2])
+			d.Column, _ = strconv.Atoi(m[3])
+			diagnostics = append(diagnostics, d)
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{Message: strings.TrimPrefix(trimmed, "vet: ")})
+	}
+	unique := diagnostics[:0]
+	for _, d := range diagnostics {
+		if !seen[d] {
+			seen[d] = true
+			unique = append(unique, d)
+		}
+	}
+	return unique
+}
+
+// Explanation is a diagnostic matched by a rule, with the rule's text
+// filled in with the names from the message
+type Explanation struct {
+	Diagnostic Diagnostic
+	Rule       *Rule
+	Meaning    string
+	Fix        string
+}
+
+// Explain parses compiler or vet output and explains every diagnostic a
+// rule recognizes. The diagnostics no rule matches are returned separately.
+func Explain(output string) (explained []Explanation, unknown []Diagnostic) {
+	for _, d := range Parse(output) {
+		if e, ok := Match(d); ok {
+			explained = append(explained, e)
+		} else {
+			unknown = append(unknown, d)
+		}
+	}
+	return explained, unknown
+}
+
+// Match explains a single diagnostic with the first rule that matches it
+func Match(d Diagnostic) (Explanation, bool) {
+	for _, r := range Rules {
+		match := r.Pattern.FindStringSubmatchIndex(d.Message)
+		if match == nil {
+			continue
+		}
+		return Explanation{
+			Diagnostic: d,
+			Rule:       r,
+			Meaning:    string(r.Pattern.ExpandString(nil, r.Meaning, d.Message, match)),
+			Fix:        string(r.Pattern.ExpandString(nil, r.Fix, d.Message, match)),
+		}, true
+	}
+	return Explanation{}, false
+}
@@ -0,0 +1,132 @@
+package explain
+
+import "regexp"
+
+// Rule recognizes one kind of mistake. Meaning and Fix may refer to the
+// named groups of Pattern as ${name}.
+type Rule struct {
+	ID      string
+	Pattern *regexp.Regexp
+	Meaning string
+	Fix     string
+	Lesson  string // ID of the lesson that covers the mistake
+	Term    string // ID of the glossary term behind it, if any
+}
+
+// Rules are tried in order, so the rules for messages that contain other
+// messages, such as an impossible type assertion that explains which
+// method is missing, come first
+var Rules = []*Rule{
+	{
+		ID:      "impossible-type-assertion",
+		Pattern: regexp.MustCompile(`impossible type assertion: (?P<expr>\S+)\n\s*(?P<type>\S+) does not implement (?P<iface>\S+) \((?P<reason>[^)]*)\)`),
+		Meaning: "The assertion ${expr} can never succeed: ${type} does not implement ${iface} (${reason}), so a ${iface} can never hold a ${type}.",
+		Fix:     "Assert to a type that implements ${iface}. When its methods have pointer receivers, assert to the pointer type, as in .(*${type}).",
+		Lesson:  "type-assertion",
+		Term:    "type-assertion",
+	},
+	{
+		ID:      "impossible-type-switch-case",
+		Pattern: regexp.MustCompile(`impossible type switch case: (?P<type>\S+)\n\s*(?P<expr>\S+) \(.*?\) cannot have dynamic type \S+ \((?P<reason>[^)]*)\)`),
+		Meaning: "The case ${type} can never match: ${expr} is an interface that ${type} does not implement (${reason}).",
+		Fix:     "Switch on types that implement the interface. When their methods have pointer receivers, use the pointer type in the case, as in case *${type}.",
+		Lesson:  "type-assertion",
+		Term:    "type-switch",
+	},
+	{
+		ID:      "pointer-receiver",
+		Pattern: regexp.MustCompile(`(?P<type>\S+) does not implement (?P<iface>\S+) \(method (?P<method>\w+) has pointer receiver\)`),
+		Meaning: "${method} is declared on *${type}, a pointer receiver, so only a *${type} has it in its method set. A plain ${type} value does not implement ${iface}.",
+		Fix:     "Use a pointer where a ${iface} is expected, such as &value, or declare ${method} with a value receiver if it does not need to change the value.",
+		Lesson:  "interface-implementation",
+		Term:    "method-set",
+	},
+	{
+		ID:      "method-name-case",
+		Pattern: regexp.MustCompile(`(?P<type>\S+) does not implement (?P<iface>\S+) \(missing method (?P<method>\w+)\)\n\s*have (?P<have>\w+)\(`),
+		Meaning: "${type} has a method called ${have}, but ${iface} needs ${method}. Method names must match exactly, including capitalization, and a lowercase name is not exported.",
+		Fix:     "Rename ${have} to ${method}.",
+		Lesson:  "interface-implementation",
+		Term:    "implicit-implementation",
+	},
+	{
+		ID:      "missing-method",
+		Pattern: regexp.MustCompile(`(?P<type>\S+) does not implement (?P<iface>\S+) \(missing (?:method )?(?P<method>\w+)(?: method)?\)`),
+		Meaning: "${iface} requires the method ${method} and ${type} does not have it. A type implements an interface only when it has every method the interface lists.",
+		Fix:     "Add the method ${method} to ${type} with the signature ${iface} declares.",
+		Lesson:  "interface-implementation",
+		Term:    "implicit-implementation",
+	},
+	{
+		ID:      "wrong-method-signature",
+		Pattern: regexp.MustCompile(`(?P<type>\S+) does not implement (?P<iface>\S+) \(wrong type for method (?P<method>\w+)\)(?:\n\s*have (?P<have>.+)\n\s*want (?P<want>.+))?`),
+		Meaning: "${type} has a method ${method}, but not with the parameters and results ${iface} requires. Signatures must match exactly.",
+		Fix:     "Change the method to match the interface: ${want}",
+		Lesson:  "interface-implementation",
+		Term:    "implicit-implementation",
+	},
+	{
+		ID:      "assertion-on-concrete-type",
+		Pattern: regexp.MustCompile(`invalid operation: (?P<expr>\S+) \((?:variable|value) of (?:\w+ )?type (?P<type>\S+)\) is not an interface`),
+		Meaning: "Type assertions and type switches only work on interface values. ${expr} already has the concrete type ${type}, so there is nothing to find out.",
+		Fix:     "Use ${expr} directly, or store it in an interface variable first.",
+		Lesson:  "type-assertion",
+		Term:    "type-assertion",
+	},
+	{
+		ID:      "interface-to-concrete",
+		Pattern: regexp.MustCompile(`cannot use (?P<expr>\S+) \(variable of interface type (?P<iface>\S+)\) as (?P<type>\S+) value`),
+		Meaning: "${expr} is a ${iface} interface value. The compiler cannot know that it holds a ${type}, so it will not convert it for you.",
+		Fix:     "Get the concrete value with a type assertion, checking that it worked: v, ok := ${expr}.(${type})",
+		Lesson:  "type-assertion",
+		Term:    "comma-ok",
+	},
+	{
+		ID:      "method-not-in-interface",
+		Pattern: regexp.MustCompile(`(?P<expr>\S+) undefined \(type (?P<type>\S+) has no field or method (?P<name>\w+)`),
+		Meaning: "${type} has no ${name}. When ${type} is an interface, only the methods it declares can be used through it, even if the value stored in it has more.",
+		Fix:     "Assert to the concrete type first, for example c, ok := x.(Concrete), and use c.${name}; or add ${name} to the interface as a method.",
+		Lesson:  "type-assertion",
+		Term:    "type-assertion",
+	},
+	{
+		ID:      "enum-type-mismatch",
+		Pattern: regexp.MustCompile(`cannot use (?P<name>\S+) \(constant (?P<value>\S+) of (?:\w+ )?type (?P<from>\S+)\) as (?P<to>\S+) value`),
+		Meaning: "${name} is a ${from} constant, and ${to} is a different type even if both are numbers underneath. This is the type safety that named enum types give you.",
+		Fix:     "Use a ${to} constant here. Convert with ${to}(${name}) only if the two values really mean the same thing.",
+		Lesson:  "basic-enums",
+		Term:    "type-safety",
+	},
+	{
+		ID:      "untyped-constant",
+		Pattern: regexp.MustCompile(`cannot use (?P<name>\S+) \(untyped (?P<kind>\w+) constant(?: [^)]*)?\) as (?P<to>\S+) value`),
+		Meaning: "${name} is an untyped ${kind} constant. Untyped constants only convert to types with a compatible underlying type, so it cannot become a ${to}.",
+		Fix:     "Give the enum a type of its own, such as type Status int with const ${name} Status = ..., and use that type for the parameter; or pass a ${to} value.",
+		Lesson:  "basic-enums",
+		Term:    "type-safety",
+	},
+	{
+		ID:      "mismatched-enum-types",
+		Pattern: regexp.MustCompile(`invalid operation: (?P<expr>.+) \(mismatched types (?P<a>\S+) and (?P<b>\S+)\)`),
+		Meaning: "Both sides of ${expr} must have the same type, but one is a ${a} and the other a ${b}. Different named types never mix, even when both are ints.",
+		Fix:     "Compare values of the same type. If you really mean to compare the numbers, convert both explicitly, as in int(x) == int(y).",
+		Lesson:  "basic-enums",
+		Term:    "type-safety",
+	},
+	{
+		ID:      "recursive-string",
+		Pattern: regexp.MustCompile(`format (?P<verb>%\w) with arg (?P<arg>\S+) causes recursive (?P<method>\S+) method call`),
+		Meaning: "Formatting ${arg} with ${verb} inside its own String method calls String again, which recurses until the program runs out of stack.",
+		Fix:     "Format the underlying value instead, such as fmt.Sprintf(\"%d\", int(${arg})), or look the name up in a table.",
+		Lesson:  "string-enums",
+		Term:    "fmt-stringer",
+	},
+	{
+		ID:      "duplicate-enum-case",
+		Pattern: regexp.MustCompile(`duplicate case (?P<value>\S+) \((?P<desc>[^)]*)\) in (?:expression )?switch`),
+		Meaning: "Two cases of the switch have the value ${value}. With enums this usually means two constants share a value, or a plain number repeats a named constant.",
+		Fix:     "Remove the duplicate case, or give every constant its own value, for example with iota.",
+		Lesson:  "iota-enums",
+		Term:    "iota",
+	},
+}
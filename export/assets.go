@@ -0,0 +1,255 @@
+package export
+
+// siteTemplates holds the html/template definitions for every page of the
+// static site
+const siteTemplates = `
+{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · Go Interface &amp; Enum Explorer</title>
+<link rel="stylesheet" href="style.css">
+</head>
+{{end}}
+
+{{define "sidebar"}}<nav class="sidebar">
+<a class="brand" href="index.html">Go Interface &amp; Enum Explorer</a>
+<input id="search" type="search" placeholder="Search lessons…" autocomplete="off">
+<ul id="search-results"></ul>
+{{range .Nav}}<h3>{{.Title}}</h3>
+<ul>
+{{range .Lessons}}<li><a href="{{.URL}}"{{if .Current}} class="current"{{end}}>{{.Title}}</a></li>
+{{end}}</ul>
+{{end}}<p class="print-link"><a href="glossary.html">Glossary</a> · <a href="print.html">Printable version</a></p>
+</nav>
+{{end}}
+
+{{define "scripts"}}<script src="search-index.js"></script>
+<script src="search.js"></script>
+{{end}}
+
+{{define "lesson"}}<article class="lesson" id="{{.ID}}">
+<p class="category">{{.Category}}</p>
+<h1>{{.Title}}</h1>
+{{range .Sections}}<section class="{{.Class}}">
+<h2 class="section-title">{{.Title}}</h2>
+{{.HTML}}
+</section>
+{{end}}</article>
+{{end}}
+
+{{define "lesson-page"}}{{template "head" .}}<body>
+{{template "sidebar" .}}<main>
+{{range .Lesson.Changes}}<p class="changes"><a href="{{.URL}}">What changed since {{.Title}}</a></p>
+{{end}}{{template "lesson" .Lesson}}<nav class="prev-next">
+{{with .Prev}}<a class="prev" href="{{.URL}}">← {{.Title}}</a>{{end}}
+{{with .Next}}<a class="next" href="{{.URL}}">{{.Title}} →</a>{{end}}
+</nav>
+</main>
+{{template "scripts" .}}</body>
+</html>
+{{end}}
+
+{{define "index-page"}}{{template "head" .}}<body>
+{{template "sidebar" .}}<main>
+<h1>Go Interface &amp; Enum Explorer</h1>
+<p>An educational tour of Go interfaces and enums through progressive examples.
+Each lesson explains a concept, shows example code and the output of running it,
+and ends with key takeaways.</p>
+<h2>Learning path</h2>
+<ol class="path">
+{{range .All}}<li><a href="{{.URL}}">{{.Title}}</a> <span class="category">{{.Category}}</span></li>
+{{end}}</ol>
+</main>
+{{template "scripts" .}}</body>
+</html>
+{{end}}
+
+{{define "glossary-page"}}{{template "head" .}}<body>
+{{template "sidebar" .}}<main>
+<h1>Glossary</h1>
+<dl class="glossary">
+{{range .Terms}}<dt id="term-{{.ID}}">{{.Name}}</dt>
+<dd>
+<p>{{.Definition}}</p>
+{{with .Example}}<pre class="code"><code>{{.}}</code></pre>
+{{end}}{{with .Lessons}}<p class="used-in">Used in: {{range $i, $l := .}}{{if $i}}, {{end}}<a href="{{$l.URL}}">{{$l.Title}}</a>{{end}}</p>
+{{end}}</dd>
+{{end}}</dl>
+</main>
+{{template "scripts" .}}</body>
+</html>
+{{end}}
+
+{{define "compare-page"}}{{template "head" .}}<body>
+{{template "sidebar" .}}<main>
+{{with .Compare}}<p class="category">Compare</p>
+<h1><a href="{{.From.URL}}">{{.From.Title}}</a> → <a href="{{.To.URL}}">{{.To.Title}}</a></h1>
+<section class="concepts">
+<h2 class="section-title">New in {{.To.Title}}</h2>
+{{if .Introduced}}<ul>
+{{range .Introduced}}<li><strong>{{if .TermURL}}<a class="term" href="{{.TermURL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</strong>{{with .Replaces}} (replaces {{.}}){{end}}: {{.Description}}</li>
+{{end}}</ul>
+{{else}}<p>No new concepts.</p>
+{{end}}{{with .Dropped}}<p>No longer used: {{range $i, $c := .}}{{if $i}}, {{end}}{{$c.Name}}{{end}}</p>
+{{end}}</section>
+<section class="code">
+<h2 class="section-title">Code changes: {{.Added}} lines added, {{.Removed}} removed</h2>
+{{range .Hunks}}<table class="diff">
+<tr class="hunk"><td colspan="4">{{.Header}}</td></tr>
+{{range .Lines}}<tr class="{{.Class}}"><td class="num">{{.Old}}</td><td class="num">{{.New}}</td><td class="sign">{{.Sign}}</td><td><code>{{.HTML}}</code></td></tr>
+{{end}}</table>
+{{else}}<p>The code of both lessons is the same.</p>
+{{end}}</section>
+{{end}}</main>
+{{template "scripts" .}}</body>
+</html>
+{{end}}
+
+{{define "print-page"}}{{template "head" .}}<body class="print">
+<main>
+<h1>Go Interface &amp; Enum Explorer</h1>
+<ol class="toc">
+{{range .All}}<li><a href="#{{.ID}}">{{.Title}}</a></li>
+{{end}}</ol>
+{{range .All}}{{template "lesson" .}}{{end}}</main>
+</body>
+</html>
+{{end}}
+`
+
+// siteCSS styles the static site, including the syntax highlighting classes
+// produced by HighlightHTML
+const siteCSS = `body {
+  margin: 0;
+  font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif;
+  line-height: 1.5;
+  color: #1f2328;
+  display: flex;
+}
+.sidebar {
+  position: sticky;
+  top: 0;
+  height: 100vh;
+  overflow-y: auto;
+  width: 17rem;
+  flex-shrink: 0;
+  box-sizing: border-box;
+  padding: 1rem;
+  background: #f6f8fa;
+  border-right: 1px solid #d0d7de;
+}
+.sidebar .brand { display: block; font-weight: bold; margin-bottom: 1rem; color: #00add8; text-decoration: none; }
+.sidebar h3 { font-size: 0.8rem; text-transform: uppercase; color: #57606a; margin: 1rem 0 0.25rem; }
+.sidebar ul { list-style: none; padding: 0; margin: 0; }
+.sidebar li a { display: block; padding: 0.15rem 0.5rem; border-radius: 4px; color: #1f2328; text-decoration: none; }
+.sidebar li a:hover { background: #eaeef2; }
+.sidebar li a.current { background: #00add8; color: #fff; }
+#search { width: 100%; box-sizing: border-box; padding: 0.3rem; }
+#search-results li { margin: 0.25rem 0; font-size: 0.9rem; }
+#search-results .snippet { display: block; color: #57606a; font-size: 0.8rem; }
+main { flex: 1; max-width: 60rem; padding: 1rem 2rem 3rem; }
+.category { color: #57606a; text-transform: uppercase; font-size: 0.8rem; margin: 0; }
+.section-title { font-size: 0.85rem; letter-spacing: 0.05em; border-bottom: 2px solid; padding-bottom: 0.2rem; }
+.explanation .section-title { color: #0969da; }
+.code .section-title { color: #1a7f37; }
+.output .section-title { color: #9a6700; }
+.takeaways .section-title { color: #8250df; }
+li.nested { margin-left: 1.5rem; }
+a.term { color: inherit; text-decoration: underline dotted #0969da; }
+.callout, .callout-ref { color: #0969da; font-weight: 600; text-decoration: none; }
+.callout { margin-left: 0.5rem; border-radius: 3px; }
+.callout:target { background: #fff8c5; outline: 2px solid #d4a72c; }
+.callout-ref:hover { background: #ddf4ff; border-radius: 3px; }
+.glossary dt { font-weight: 600; font-size: 1.1rem; margin-top: 1.5rem; }
+.glossary dd { margin-left: 0; }
+.glossary .used-in { color: #57606a; font-size: 0.9rem; }
+pre { overflow-x: auto; padding: 0.75rem; border-radius: 6px; font-size: 0.85rem; }
+pre.code { background: #f6f8fa; border: 1px solid #d0d7de; }
+pre.output { background: #1f2328; color: #e6edf3; }
+.tok-keyword { color: #cf222e; font-weight: 600; }
+.tok-type { color: #0550ae; }
+.tok-builtin { color: #8250df; }
+.tok-string { color: #0a3069; }
+.tok-number { color: #0550ae; }
+.tok-comment { color: #6e7781; font-style: italic; }
+.changes { float: right; font-size: 0.9rem; margin: 0; }
+table.diff { width: 100%; border-collapse: collapse; font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 0.8rem; margin-bottom: 1rem; border: 1px solid #d0d7de; }
+table.diff td { padding: 0 0.5rem; white-space: pre; vertical-align: top; }
+table.diff td.num { color: #6e7781; text-align: right; width: 2.5rem; user-select: none; }
+table.diff td.sign { width: 1rem; user-select: none; }
+table.diff tr.add { background: #e6ffec; }
+table.diff tr.del { background: #ffebe9; }
+table.diff tr.hunk td { background: #ddf4ff; color: #57606a; padding: 0.2rem 0.5rem; }
+.prev-next { display: flex; justify-content: space-between; margin-top: 2rem; }
+body.print { display: block; }
+body.print main { max-width: none; }
+body.print .lesson { page-break-before: always; }
+@media print {
+  .sidebar, .prev-next, .print-link, .changes { display: none; }
+  body { display: block; }
+  main { max-width: none; padding: 0; }
+  pre { white-space: pre-wrap; }
+  pre.output { background: none; color: inherit; border: 1px solid #999; }
+}
+`
+
+// searchJS is the client-side search. It reads the index from
+// window.SEARCH_INDEX (search-index.js) and falls back to fetching
+// search-index.json.
+const searchJS = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  if (!input || !results) return;
+
+  var index = window.SEARCH_INDEX;
+  if (!index && window.fetch) {
+    fetch("search-index.json")
+      .then(function (r) { return r.json(); })
+      .then(function (data) { index = data; })
+      .catch(function () {});
+  }
+
+  function snippet(text, term) {
+    var i = text.toLowerCase().indexOf(term);
+    if (i < 0) return "";
+    var start = Math.max(0, i - 40);
+    return (start > 0 ? "…" : "") + text.substr(start, 100).replace(/\s+/g, " ") + "…";
+  }
+
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.innerHTML = "";
+    if (!index || terms.length === 0) return;
+
+    var hits = [];
+    index.forEach(function (entry) {
+      var title = entry.title.toLowerCase();
+      var text = entry.text.toLowerCase();
+      var score = 0;
+      for (var i = 0; i < terms.length; i++) {
+        var inTitle = title.indexOf(terms[i]) >= 0;
+        var count = text.split(terms[i]).length - 1;
+        if (!inTitle && count === 0) return;
+        score += (inTitle ? 10 : 0) + count;
+      }
+      hits.push({ entry: entry, score: score });
+    });
+
+    hits.sort(function (a, b) { return b.score - a.score; });
+    hits.slice(0, 10).forEach(function (hit) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = hit.entry.url;
+      a.textContent = hit.entry.title;
+      var span = document.createElement("span");
+      span.className = "snippet";
+      span.textContent = snippet(hit.entry.text, terms[0]);
+      li.appendChild(a);
+      li.appendChild(span);
+      results.appendChild(li);
+    });
+  });
+})();
+`
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+
+	"go-interface-enum-explorer/compare"
+	"go-interface-enum-explorer/lessons"
+)
+
+// comparisonView is a comparison of two lessons prepared for the compare
+// page
+type comparisonView struct {
+	From, To       navLink
+	URL            string
+	Introduced     []conceptView
+	Dropped        []conceptView
+	Added, Removed int
+	Hunks          []hunkView
+}
+
+type conceptView struct {
+	Name        string
+	Description string
+	Replaces    string
+	TermURL     string
+}
+
+type hunkView struct {
+	Header string
+	Lines  []diffLineView
+}
+
+type diffLineView struct {
+	Class    string // "add", "del" or "ctx"
+	Sign     string
+	Old, New string
+	HTML     template.HTML
+}
+
+// comparePageURL returns the file name of the page comparing two lessons
+func comparePageURL(from, to *lessons.Lesson) string {
+	return "compare-" + from.ID + "-" + to.ID + ".html"
+}
+
+// directPrerequisites returns, for every exported lesson, the exported
+// lessons it directly builds on, in the order it lists them. Its compare
+// pages are made against these, so only lessons that form a progression,
+// such as Iota Enums and String Enums, are compared.
+func directPrerequisites(all []*lessons.Lesson) map[string][]*lessons.Lesson {
+	byID := make(map[string]*lessons.Lesson, len(all))
+	for _, l := range all {
+		byID[l.ID] = l
+	}
+	prerequisites := make(map[string][]*lessons.Lesson)
+	for _, l := range all {
+		for _, id := range l.Prerequisites {
+			if p := byID[id]; p != nil {
+				prerequisites[l.ID] = append(prerequisites[l.ID], p)
+			}
+		}
+	}
+	return prerequisites
+}
+
+func newComparisonView(c *compare.Comparison) *comparisonView {
+	view := &comparisonView{
+		From:    navLink{Title: c.From.Title, URL: pageURL(c.From)},
+		To:      navLink{Title: c.To.Title, URL: pageURL(c.To)},
+		URL:     comparePageURL(c.From, c.To),
+		Added:   c.Added,
+		Removed: c.Removed,
+	}
+	for _, change := range c.Introduced {
+		concept := newConceptView(change.Concept)
+		if change.Replaces != nil {
+			concept.Replaces = change.Replaces.Name
+		}
+		view.Introduced = append(view.Introduced, concept)
+	}
+	for _, concept := range c.Dropped {
+		view.Dropped = append(view.Dropped, newConceptView(concept))
+	}
+
+	for _, hunk := range c.Hunks {
+		oldStart, oldCount, newStart, newCount := hunk.Span()
+		h := hunkView{Header: fmt.Sprintf("Lines %d–%d → %d–%d", oldStart, oldStart+oldCount-1, newStart, newStart+newCount-1)}
+		for _, l := range hunk.Lines {
+			line := diffLineView{HTML: template.HTML(HighlightHTML(l.Text))}
+			switch l.Op {
+			case compare.Insert:
+				line.Class, line.Sign = "add", "+"
+			case compare.Delete:
+				line.Class, line.Sign = "del", "-"
+			default:
+				line.Class, line.Sign = "ctx", " "
+			}
+			if l.Old > 0 {
+				line.Old = fmt.Sprint(l.Old)
+			}
+			if l.New > 0 {
+				line.New = fmt.Sprint(l.New)
+			}
+			h.Lines = append(h.Lines, line)
+		}
+		view.Hunks = append(view.Hunks, h)
+	}
+	return view
+}
+
+func newConceptView(c *compare.Concept) conceptView {
+	view := conceptView{Name: c.Name, Description: c.Description}
+	if c.Term != "" {
+		view.TermURL = glossaryPage + "#term-" + c.Term
+	}
+	return view
+}
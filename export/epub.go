@@ -0,0 +1,241 @@
+package export
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"fmt"
+	"hash/crc32"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go-interface-enum-explorer/lessons"
+)
+
+// bookTitle is used as the title of the exported books
+const bookTitle = "Go Interface & Enum Explorer"
+
+// epubChapter is one lesson inside the EPUB
+type epubChapter struct {
+	Number int
+	File   string
+	Lesson *lessonView
+}
+
+// epubFile is a document in the EPUB rendered from one of epubTemplates
+type epubFile struct {
+	name     string
+	template string
+	data     interface{}
+}
+
+// epubView is the data behind the EPUB package documents
+type epubView struct {
+	Title    string
+	ID       string
+	Modified string
+	Chapters []epubChapter
+}
+
+// EPUB writes the tutorial as an EPUB 3 book to path, with one chapter per
+// lesson in tutorial order, a table of contents and embedded code styling
+func EPUB(path string, all []*lessons.Lesson) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := writeEPUB(f, all); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeEPUB(w io.Writer, all []*lessons.Lesson) error {
+	tmpl, err := template.New("epub").Parse(epubTemplates)
+	if err != nil {
+		return fmt.Errorf("parsing templates: %w", err)
+	}
+
+	book := epubView{
+		Title:    bookTitle,
+		ID:       bookID(all),
+		Modified: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	for i, l := range all {
+		book.Chapters = append(book.Chapters, epubChapter{
+			Number: i + 1,
+			File:   fmt.Sprintf("chapter-%02d-%s.xhtml", i+1, l.ID),
+			Lesson: newLessonView(l, ""),
+		})
+	}
+
+	zw := zip.NewWriter(w)
+
+	// The mimetype entry must come first, uncompressed and without a data
+	// descriptor, so readers can sniff it at a fixed offset
+	const mimetypeContent = "application/epub+zip"
+	mimetype, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(mimetypeContent)),
+		CompressedSize64:   uint64(len(mimetypeContent)),
+		UncompressedSize64: uint64(len(mimetypeContent)),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, mimetypeContent); err != nil {
+		return err
+	}
+
+	files := []epubFile{
+		{"META-INF/container.xml", "container", book},
+		{"OEBPS/content.opf", "package", book},
+		{"OEBPS/nav.xhtml", "nav", book},
+		{"OEBPS/toc.ncx", "ncx", book},
+	}
+	for _, ch := range book.Chapters {
+		files = append(files, epubFile{"OEBPS/" + ch.File, "chapter", ch})
+	}
+
+	for _, file := range files {
+		entry, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		// html/template would escape the XML declaration, so it is written here
+		if _, err := io.WriteString(entry, xmlDeclaration); err != nil {
+			return err
+		}
+		if err := tmpl.ExecuteTemplate(entry, file.template, file.data); err != nil {
+			return fmt.Errorf("rendering %s: %w", file.name, err)
+		}
+	}
+
+	style, err := zw.Create("OEBPS/style.css")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(style, epubCSS); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// bookID derives a stable urn:uuid identifier from the lessons in the book,
+// so exporting the same tutorial twice yields the same identifier
+func bookID(all []*lessons.Lesson) string {
+	ids := make([]string, len(all))
+	for i, l := range all {
+		ids[i] = l.ID
+	}
+	sum := sha1.Sum([]byte(bookTitle + "\n" + strings.Join(ids, "\n")))
+	sum[6] = sum[6]&0x0f | 0x50 // version 5
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// xmlDeclaration starts every XML document in the EPUB
+const xmlDeclaration = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+// epubTemplates holds the container, package, navigation and chapter
+// documents of the EPUB
+const epubTemplates = `
+{{define "container"}}<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+{{end}}
+
+{{define "package"}}<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="en">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">{{.ID}}</dc:identifier>
+    <dc:title>{{.Title}}</dc:title>
+    <dc:language>en</dc:language>
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="style" href="style.css" media-type="text/css"/>
+{{range .Chapters}}    <item id="chapter-{{.Number}}" href="{{.File}}" media-type="application/xhtml+xml"/>
+{{end}}  </manifest>
+  <spine toc="ncx">
+{{range .Chapters}}    <itemref idref="chapter-{{.Number}}"/>
+{{end}}  </spine>
+</package>
+{{end}}
+
+{{define "nav"}}<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="en" lang="en">
+<head>
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>Contents</h1>
+    <ol>
+{{range .Chapters}}      <li><a href="{{.File}}">{{.Lesson.Title}}</a></li>
+{{end}}    </ol>
+  </nav>
+</body>
+</html>
+{{end}}
+
+{{define "ncx"}}<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="{{.ID}}"/>
+  </head>
+  <docTitle><text>{{.Title}}</text></docTitle>
+  <navMap>
+{{range .Chapters}}    <navPoint id="nav-{{.Number}}" playOrder="{{.Number}}">
+      <navLabel><text>{{.Lesson.Title}}</text></navLabel>
+      <content src="{{.File}}"/>
+    </navPoint>
+{{end}}  </navMap>
+</ncx>
+{{end}}
+
+{{define "chapter"}}<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en" lang="en">
+<head>
+  <title>{{.Lesson.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+<p class="category">{{.Lesson.Category}}</p>
+<h1>{{.Number}}. {{.Lesson.Title}}</h1>
+{{range .Lesson.Sections}}<section class="{{.Class}}">
+<h2 class="section-title">{{.Title}}</h2>
+{{.HTML}}
+</section>
+{{end}}</body>
+</html>
+{{end}}
+`
+
+// epubCSS is the stylesheet embedded in the EPUB. E-readers vary in what
+// they support, so it sticks to simple properties.
+const epubCSS = `body { font-family: serif; line-height: 1.4; }
+h1 { font-size: 1.5em; }
+.category { text-transform: uppercase; font-size: 0.8em; color: #555; margin: 0; }
+.section-title { font-size: 0.9em; letter-spacing: 0.05em; border-bottom: 1px solid #999; }
+li.nested { margin-left: 1.5em; }
+pre { font-family: monospace; font-size: 0.75em; white-space: pre-wrap; padding: 0.5em; border: 1px solid #ccc; }
+pre.output { background: #f2f2f2; }
+.term { border-bottom: 1px dotted #555; }
+.callout, .callout-ref { font-weight: bold; text-decoration: none; }
+.tok-keyword { color: #a0002a; font-weight: bold; }
+.tok-type { color: #0550ae; }
+.tok-builtin { color: #6f42c1; }
+.tok-string { color: #0a3069; }
+.tok-number { color: #0550ae; }
+.tok-comment { color: #6e7781; font-style: italic; }
+`
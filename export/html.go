@@ -0,0 +1,419 @@
+// Package export renders the tutorial into formats that can be read without
+// running the explorer, such as a static HTML site.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-interface-enum-explorer/compare"
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/utils"
+)
+
+// navCategory is one group of links in the sidebar
+type navCategory struct {
+	Title   string
+	Lessons []navLink
+}
+
+type navLink struct {
+	Title   string
+	URL     string
+	Current bool
+}
+
+// lessonView is a lesson prepared for the HTML templates
+type lessonView struct {
+	ID       string
+	Title    string
+	Category string
+	URL      string
+	Sections []sectionView
+	// Changes links to the pages comparing the lesson with each lesson it
+	// directly builds on
+	Changes []navLink
+}
+
+type sectionView struct {
+	Class string
+	Title string
+	HTML  template.HTML
+}
+
+// termView is a glossary term prepared for the glossary page
+type termView struct {
+	ID         string
+	Name       string
+	Definition string
+	Example    template.HTML
+	Lessons    []navLink
+}
+
+// pageView is the data behind every generated page
+type pageView struct {
+	Title   string
+	Nav     []navCategory
+	Lesson  *lessonView
+	Prev    *lessonView
+	Next    *lessonView
+	All     []*lessonView
+	Terms   []termView
+	Compare *comparisonView
+}
+
+// glossaryPage is the file name of the glossary page of the site
+const glossaryPage = "glossary.html"
+
+// searchEntry is one record of the client-side search index
+type searchEntry struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Category string `json:"category"`
+	URL      string `json:"url"`
+	Text     string `json:"text"`
+}
+
+// HTML writes the tutorial as a static site into dir: one page per lesson
+// with a navigation sidebar, an index page, a glossary, a printable single
+// page, pages comparing each lesson's code with the lessons it directly
+// builds on and a search index used by a small client-side search
+func HTML(dir string, all []*lessons.Lesson) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	tmpl, err := template.New("site").Parse(siteTemplates)
+	if err != nil {
+		return fmt.Errorf("parsing templates: %w", err)
+	}
+
+	views := make([]*lessonView, len(all))
+	prerequisites := directPrerequisites(all)
+	for i, l := range all {
+		views[i] = newLessonView(l, glossaryPage)
+		for _, p := range prerequisites[l.ID] {
+			view := newComparisonView(compare.Lessons(p, l))
+			views[i].Changes = append(views[i].Changes, navLink{Title: p.Title, URL: view.URL})
+			page := pageView{Title: view.From.Title + " → " + view.To.Title, Nav: navigation(all, l.ID), Compare: view}
+			if err := writeTemplate(tmpl, "compare-page", filepath.Join(dir, view.URL), page); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, view := range views {
+		page := pageView{Title: view.Title, Nav: navigation(all, view.ID), Lesson: view}
+		if i > 0 {
+			page.Prev = views[i-1]
+		}
+		if i < len(views)-1 {
+			page.Next = views[i+1]
+		}
+		if err := writeTemplate(tmpl, "lesson-page", filepath.Join(dir, view.URL), page); err != nil {
+			return err
+		}
+	}
+
+	index := pageView{Title: "Go Interface & Enum Explorer", Nav: navigation(all, ""), All: views}
+	if err := writeTemplate(tmpl, "index-page", filepath.Join(dir, "index.html"), index); err != nil {
+		return err
+	}
+	glossary := pageView{Title: "Glossary", Nav: navigation(all, ""), Terms: glossaryTerms(all)}
+	if err := writeTemplate(tmpl, "glossary-page", filepath.Join(dir, glossaryPage), glossary); err != nil {
+		return err
+	}
+	printable := pageView{Title: "Go Interface & Enum Explorer", All: views}
+	if err := writeTemplate(tmpl, "print-page", filepath.Join(dir, "print.html"), printable); err != nil {
+		return err
+	}
+
+	indexJSON, err := json.MarshalIndent(searchIndex(all), "", "  ")
+	if err != nil {
+		return fmt.Errorf("building search index: %w", err)
+	}
+
+	files := map[string]string{
+		"style.css":         siteCSS,
+		"search.js":         searchJS,
+		"search-index.json": string(indexJSON),
+		// The same index as a script, so search also works from file:// URLs
+		"search-index.js": "window.SEARCH_INDEX = " + string(indexJSON) + ";\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func writeTemplate(tmpl *template.Template, name, path string, data pageView) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := tmpl.ExecuteTemplate(f, name, data); err != nil {
+		return fmt.Errorf("rendering %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// pageURL returns the file name of a lesson's page
+func pageURL(l *lessons.Lesson) string {
+	return l.ID + ".html"
+}
+
+func navigation(all []*lessons.Lesson, current string) []navCategory {
+	var nav []navCategory
+	for _, c := range lessons.Categories {
+		group := navCategory{Title: c.Title}
+		for _, l := range all {
+			if l.Category == c.ID {
+				group.Lessons = append(group.Lessons, navLink{Title: l.Title, URL: pageURL(l), Current: l.ID == current})
+			}
+		}
+		if len(group.Lessons) > 0 {
+			nav = append(nav, group)
+		}
+	}
+	return nav
+}
+
+// glossaryTerms prepares the glossary, linking each term to the exported
+// lessons that use it
+func glossaryTerms(all []*lessons.Lesson) []termView {
+	exported := make(map[string]bool, len(all))
+	for _, l := range all {
+		exported[l.ID] = true
+	}
+
+	terms := make([]termView, 0, len(lessons.Glossary))
+	for _, t := range lessons.Glossary {
+		view := termView{ID: t.ID, Name: t.Name, Definition: t.Definition}
+		if t.Example != "" {
+			view.Example = template.HTML(HighlightHTML(t.Example))
+		}
+		for _, l := range t.Lessons() {
+			if exported[l.ID] {
+				view.Lessons = append(view.Lessons, navLink{Title: l.Title, URL: pageURL(l)})
+			}
+		}
+		terms = append(terms, view)
+	}
+	return terms
+}
+
+// newLessonView prepares a lesson for the templates. Glossary terms in the
+// explanation link to their entry on glossaryURL, or only show their
+// definition on hover when glossaryURL is empty.
+func newLessonView(l *lessons.Lesson, glossaryURL string) *lessonView {
+	view := &lessonView{
+		ID:       l.ID,
+		Title:    l.Title,
+		Category: lessons.CategoryTitle(l.Category),
+		URL:      pageURL(l),
+	}
+	sections, callouts := utils.ExtractCallouts(l.Sections())
+	for _, s := range sections {
+		view.Sections = append(view.Sections, sectionView{
+			Class: s.Kind.Name(),
+			Title: s.Kind.Title(),
+			HTML:  sectionHTML(l.ID, s, callouts, glossaryURL),
+		})
+	}
+	return view
+}
+
+func sectionHTML(lessonID string, s utils.Section, callouts []utils.Callout, glossaryURL string) template.HTML {
+	switch s.Kind {
+	case utils.SectionExplanation:
+		return textHTML(s.Text, func(text string) string {
+			return calloutRefsHTML(lessonID, termHTML(text, glossaryURL), callouts)
+		})
+	case utils.SectionCode:
+		return template.HTML(`<pre class="code"><code>` + calloutsHTML(lessonID, HighlightHTML(s.Text), s.Callouts) + `</code></pre>`)
+	case utils.SectionOutput:
+		return template.HTML(`<pre class="output">` + template.HTMLEscapeString(utils.StripANSI(s.Text)) + `</pre>`)
+	default:
+		return textHTML(s.Text, func(text string) string {
+			return calloutRefsHTML(lessonID, template.HTMLEscapeString(text), callouts)
+		})
+	}
+}
+
+// calloutID is the anchor of a callout badge; it includes the lesson ID so
+// badges stay unique on the printable page
+func calloutID(lessonID string, n int) string {
+	return fmt.Sprintf("%s-callout-%d", lessonID, n)
+}
+
+// calloutsHTML appends a badge to every highlighted code line that has a
+// callout
+func calloutsHTML(lessonID, code string, callouts []utils.Callout) string {
+	if len(callouts) == 0 {
+		return code
+	}
+	lines := strings.Split(code, "\n")
+	for _, c := range callouts {
+		if c.Line >= 1 && c.Line <= len(lines) {
+			lines[c.Line-1] += fmt.Sprintf(` <span class="callout" id="%s">%s</span>`, calloutID(lessonID, c.Number), utils.CalloutBadge(c.Number))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// calloutRefsHTML turns the escaped <n> references in already escaped text
+// into links to the code line, which show the line when hovered
+func calloutRefsHTML(lessonID, escaped string, callouts []utils.Callout) string {
+	if len(callouts) == 0 {
+		return escaped
+	}
+	return escapedCalloutRef.ReplaceAllStringFunc(escaped, func(match string) string {
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(match, "&lt;"), "&gt;"))
+		for _, c := range callouts {
+			if c.Number == n {
+				return fmt.Sprintf(`<a class="callout-ref" href="#%s" title="%s">%s</a>`,
+					calloutID(lessonID, n), template.HTMLEscapeString(fmt.Sprintf("Line %d: %s", c.Line, c.Code)), utils.CalloutBadge(n))
+			}
+		}
+		return match
+	})
+}
+
+// escapedCalloutRef matches a callout reference after HTML escaping
+var escapedCalloutRef = regexp.MustCompile(`&lt;[0-9]+&gt;`)
+
+// HighlightHTML returns Go source as HTML with every token wrapped in a span
+// whose class names its highlighting class, such as "tok-keyword"
+func HighlightHTML(code string) string {
+	var b strings.Builder
+	for _, tok := range utils.TokenizeGo(code) {
+		text := template.HTMLEscapeString(tok.Text)
+		if tok.Class == utils.TokenPlain {
+			b.WriteString(text)
+			continue
+		}
+		fmt.Fprintf(&b, `<span class="tok-%s">%s</span>`, tok.Class.Name(), text)
+	}
+	return b.String()
+}
+
+// TextHTML turns the plain-text layout used by explanations and takeaways
+// into HTML: underlined and "#" lines become headings, "- " lines become
+// lists and everything else becomes paragraphs
+func TextHTML(text string) template.HTML {
+	return textHTML(text, template.HTMLEscapeString)
+}
+
+// textHTML is TextHTML with the HTML of paragraphs and list items produced
+// by inline, which must escape its input
+func textHTML(text string, inline func(string) string) template.HTML {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	var b strings.Builder
+	var paragraph []string
+	inList := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + inline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if inList {
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " ")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case i+1 < len(lines) && isUnderline(lines[i+1]):
+			flushParagraph()
+			closeList()
+			b.WriteString("<h2>" + template.HTMLEscapeString(trimmed) + "</h2>\n")
+			i++
+		case strings.HasPrefix(trimmed, "#"):
+			// Markdown headings, used by lessons written in Markdown
+			flushParagraph()
+			closeList()
+			b.WriteString("<h3>" + template.HTMLEscapeString(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))) + "</h3>\n")
+		case strings.HasPrefix(trimmed, "- "):
+			flushParagraph()
+			if !inList {
+				b.WriteString("<ul>\n")
+				inList = true
+			}
+			class := ""
+			if strings.HasPrefix(line, " ") {
+				class = ` class="nested"`
+			}
+			b.WriteString("<li" + class + ">" + inline(strings.TrimPrefix(trimmed, "- ")) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return template.HTML(b.String())
+}
+
+// termHTML escapes text and marks the glossary terms in it, as links to
+// their glossary entries when glossaryURL is set
+func termHTML(text, glossaryURL string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range lessons.FindTerms(text) {
+		b.WriteString(template.HTMLEscapeString(text[last:m.Start]))
+		written := template.HTMLEscapeString(text[m.Start:m.End])
+		definition := template.HTMLEscapeString(m.Term.Definition)
+		if glossaryURL == "" {
+			fmt.Fprintf(&b, `<span class="term" title="%s">%s</span>`, definition, written)
+		} else {
+			fmt.Fprintf(&b, `<a class="term" href="%s#term-%s" title="%s">%s</a>`, glossaryURL, m.Term.ID, definition, written)
+		}
+		last = m.End
+	}
+	b.WriteString(template.HTMLEscapeString(text[last:]))
+	return b.String()
+}
+
+func isUnderline(line string) bool {
+	line = strings.TrimSpace(line)
+	return line != "" && strings.Trim(line, "=") == ""
+}
+
+func searchIndex(all []*lessons.Lesson) []searchEntry {
+	entries := make([]searchEntry, 0, len(all))
+	for _, l := range all {
+		entries = append(entries, searchEntry{
+			ID:       l.ID,
+			Title:    l.Title,
+			Category: lessons.CategoryTitle(l.Category),
+			URL:      pageURL(l),
+			Text: strings.Join([]string{
+				l.Section(utils.SectionExplanation),
+				l.Section(utils.SectionTakeaways),
+				l.Section(utils.SectionCode),
+			}, "\n"),
+		})
+	}
+	return entries
+}
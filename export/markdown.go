@@ -0,0 +1,128 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/utils"
+)
+
+// Markdown writes the whole tutorial to path as one book-style Markdown
+// document, with a table of contents and one chapter per lesson
+func Markdown(path string, all []*lessons.Lesson) error {
+	if err := os.WriteFile(path, []byte(MarkdownBook(all)), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// MarkdownBook renders the lessons as a single Markdown document
+func MarkdownBook(all []*lessons.Lesson) string {
+	var b strings.Builder
+	b.WriteString("# " + bookTitle + "\n\n")
+	b.WriteString("An educational tour of Go interfaces and enums through progressive examples.\n\n")
+
+	b.WriteString("## Contents\n\n")
+	category := ""
+	for i, l := range all {
+		if l.Category != category {
+			category = l.Category
+			fmt.Fprintf(&b, "- **%s**\n", lessons.CategoryTitle(category))
+		}
+		fmt.Fprintf(&b, "  %d. [%s](#%s)\n", i+1, l.Title, l.ID)
+	}
+	b.WriteString("\n")
+
+	for i, l := range all {
+		fmt.Fprintf(&b, "<a id=\"%s\"></a>\n\n", l.ID)
+		fmt.Fprintf(&b, "## %d. %s\n\n", i+1, l.Title)
+		fmt.Fprintf(&b, "*%s*\n\n", lessons.CategoryTitle(l.Category))
+		sections, callouts := utils.ExtractCallouts(l.Sections())
+		for _, s := range sections {
+			b.WriteString(sectionMarkdown(s, callouts))
+		}
+	}
+	return b.String()
+}
+
+// sectionMarkdown renders one section of a lesson whose callouts have been
+// extracted; see utils.ExtractCallouts
+func sectionMarkdown(s utils.Section, callouts []utils.Callout) string {
+	heading := "### " + titleCase(s.Kind.Title()) + "\n\n"
+	switch s.Kind {
+	case utils.SectionCode:
+		return heading + "```go\n" + strings.Trim(calloutComments(s.Text, s.Callouts), "\n") + "\n```\n\n"
+	case utils.SectionOutput:
+		return heading + "```text\n" + strings.Trim(utils.StripANSI(s.Text), "\n") + "\n```\n\n"
+	default:
+		return heading + textMarkdown(calloutRefs(s.Text, callouts)) + "\n"
+	}
+}
+
+// calloutComments puts the badge of each callout in a comment at the end
+// of its line, so the code stays valid Go
+func calloutComments(code string, callouts []utils.Callout) string {
+	if len(callouts) == 0 {
+		return code
+	}
+	lines := strings.Split(code, "\n")
+	for _, c := range callouts {
+		if c.Line < 1 || c.Line > len(lines) {
+			continue
+		}
+		if endsInComment(lines[c.Line-1]) {
+			lines[c.Line-1] += " " + utils.CalloutBadge(c.Number)
+		} else {
+			lines[c.Line-1] += " // " + utils.CalloutBadge(c.Number)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// endsInComment reports whether a line of Go code ends with a comment
+func endsInComment(line string) bool {
+	tokens := utils.TokenizeGo(line)
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if strings.TrimSpace(tokens[i].Text) != "" {
+			return tokens[i].Class == utils.TokenComment
+		}
+	}
+	return false
+}
+
+// calloutRefs replaces the <n> references in prose with the badge the code
+// shows for callout n
+func calloutRefs(text string, callouts []utils.Callout) string {
+	return utils.ReplaceCalloutRefs(text, callouts, func(c utils.Callout) string {
+		return utils.CalloutBadge(c.Number)
+	})
+}
+
+// textMarkdown converts the plain-text layout of explanations and takeaways
+// to Markdown, turning underlined lines into headings
+func textMarkdown(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	var b strings.Builder
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " ")
+		if i+1 < len(lines) && isUnderline(lines[i+1]) {
+			b.WriteString("#### " + strings.TrimSpace(line) + "\n")
+			i++
+			continue
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// titleCase turns an upper-case section title such as "KEY TAKEAWAYS" into
+// "Key Takeaways"
+func titleCase(title string) string {
+	words := strings.Fields(strings.ToLower(title))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
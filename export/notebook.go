@@ -0,0 +1,320 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/snippet"
+	"go-interface-enum-explorer/utils"
+)
+
+// Notebook is a Jupyter notebook in nbformat v4
+type Notebook struct {
+	Cells         []Cell                 `json:"cells"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	NBFormat      int                    `json:"nbformat"`
+	NBFormatMinor int                    `json:"nbformat_minor"`
+}
+
+// Cell is one markdown or code cell of a notebook
+type Cell struct {
+	ID             string                 `json:"id"`
+	CellType       string                 `json:"cell_type"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	Source         []string               `json:"source"`
+	ExecutionCount *int                   `json:"execution_count,omitempty"`
+	Outputs        []Output               `json:"outputs,omitempty"`
+}
+
+// Output is a stream output of a code cell
+type Output struct {
+	OutputType string   `json:"output_type"`
+	Name       string   `json:"name"`
+	Text       []string `json:"text"`
+}
+
+// MarshalJSON writes code cells with their outputs and execution count
+// even when they are empty, as nbformat requires, and leaves them out of
+// markdown cells, where they are not allowed
+func (c Cell) MarshalJSON() ([]byte, error) {
+	type cell Cell
+	if c.CellType != "code" {
+		return json.Marshal(cell(c))
+	}
+	outputs := c.Outputs
+	if outputs == nil {
+		outputs = []Output{}
+	}
+	return json.Marshal(struct {
+		cell
+		ExecutionCount *int     `json:"execution_count"`
+		Outputs        []Output `json:"outputs"`
+	}{cell(c), c.ExecutionCount, outputs})
+}
+
+// Notebooks writes one .ipynb notebook per lesson into dir
+func Notebooks(dir string, all []*lessons.Lesson) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	for _, l := range all {
+		data, err := json.MarshalIndent(LessonNotebook(l), "", " ")
+		if err != nil {
+			return fmt.Errorf("encoding notebook for %s: %w", l.ID, err)
+		}
+		if err := ValidateNotebook(data); err != nil {
+			return fmt.Errorf("notebook for %s is invalid: %w", l.ID, err)
+		}
+		path := filepath.Join(dir, l.ID+".ipynb")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// LessonNotebook turns a lesson into a notebook: the explanation and
+// takeaways become markdown cells, the code becomes Go code cells and the
+// captured output becomes the output of the cell that calls main.
+//
+// The cells follow the conventions of the gophernotes Go kernel, which has
+// no package clause and does not call main by itself: imports get a cell of
+// their own, the lesson code declares main, and a final cell runs it.
+func LessonNotebook(l *lessons.Lesson) *Notebook {
+	nb := &Notebook{
+		NBFormat:      4,
+		NBFormatMinor: 5,
+		Metadata: map[string]interface{}{
+			"kernelspec": map[string]string{
+				"display_name": "Go",
+				"language":     "go",
+				"name":         "gophernotes",
+			},
+			"language_info": map[string]string{
+				"name":           "go",
+				"file_extension": ".go",
+				"mimetype":       "text/x-go",
+			},
+		},
+	}
+
+	execution := 0
+	markdown := func(text string) {
+		nb.Cells = append(nb.Cells, Cell{CellType: "markdown", Source: sourceLines(text)})
+	}
+	code := func(src string, outputs []Output) {
+		execution++
+		count := execution
+		nb.Cells = append(nb.Cells, Cell{CellType: "code", Source: sourceLines(src), ExecutionCount: &count, Outputs: outputs})
+	}
+
+	// Callout markers become badges, as in the Markdown book
+	sections, callouts := utils.ExtractCallouts(l.Sections())
+	section := func(kind utils.SectionKind) string {
+		for _, s := range sections {
+			switch {
+			case s.Kind != kind:
+			case kind == utils.SectionCode:
+				return calloutComments(s.Text, s.Callouts)
+			case kind == utils.SectionOutput:
+				return s.Text
+			default:
+				return calloutRefs(s.Text, callouts)
+			}
+		}
+		return ""
+	}
+
+	markdown(fmt.Sprintf("# %s\n\n*%s*", l.Title, lessons.CategoryTitle(l.Category)))
+	if text := section(utils.SectionExplanation); text != "" {
+		markdown(textMarkdown(text))
+	}
+	if src := section(utils.SectionCode); src != "" {
+		src = strings.Trim(src, "\n")
+		if imports := snippet.ImportBlock(snippet.Imports(src)); imports != "" {
+			code(imports, nil)
+		}
+		code(src, nil)
+
+		var outputs []Output
+		if out := programOutput(section(utils.SectionOutput)); out != "" {
+			outputs = []Output{{OutputType: "stream", Name: "stdout", Text: sourceLines(out + "\n")}}
+		}
+		code("main()", outputs)
+	}
+	if text := section(utils.SectionTakeaways); text != "" {
+		markdown(textMarkdown(text))
+	}
+
+	for i := range nb.Cells {
+		nb.Cells[i].ID = fmt.Sprintf("%s-%d", l.ID, i+1)
+		nb.Cells[i].Metadata = map[string]interface{}{}
+	}
+	return nb
+}
+
+// programOutput returns the output a lesson's program printed, without the
+// "Running the code..." line the lessons print before it
+func programOutput(text string) string {
+	text = utils.StripANSI(text)
+	text = strings.TrimPrefix(strings.TrimLeft(text, "\n"), "Running the code...")
+	return strings.Trim(text, "\n")
+}
+
+// sourceLines splits text into the list-of-lines form notebooks use, where
+// every line but the last keeps its newline
+func sourceLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.SplitAfter(strings.TrimRight(text, "\n"), "\n")
+}
+
+var cellIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ValidateNotebook checks a notebook document against the rules of the
+// nbformat v4 schema: the required top-level fields, the cell types and
+// the fields each cell type requires or forbids, and stream outputs
+func ValidateNotebook(data []byte) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("not a JSON object: %w", err)
+	}
+
+	for _, field := range []string{"cells", "metadata", "nbformat", "nbformat_minor"} {
+		if _, ok := doc[field]; !ok {
+			return fmt.Errorf("missing required field %q", field)
+		}
+	}
+	if v, ok := doc["nbformat"].(float64); !ok || v != 4 {
+		return fmt.Errorf("nbformat must be 4, got %v", doc["nbformat"])
+	}
+	minor, ok := doc["nbformat_minor"].(float64)
+	if !ok || minor < 0 || minor != float64(int(minor)) {
+		return fmt.Errorf("nbformat_minor must be a non-negative integer, got %v", doc["nbformat_minor"])
+	}
+	if _, ok := doc["metadata"].(map[string]interface{}); !ok {
+		return fmt.Errorf("metadata must be an object")
+	}
+	cells, ok := doc["cells"].([]interface{})
+	if !ok {
+		return fmt.Errorf("cells must be an array")
+	}
+
+	ids := make(map[string]bool)
+	for i, raw := range cells {
+		cell, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cell %d: must be an object", i)
+		}
+		if err := validateCell(cell, minor >= 5, ids); err != nil {
+			return fmt.Errorf("cell %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateCell(cell map[string]interface{}, needsID bool, ids map[string]bool) error {
+	allowed := map[string]bool{"id": true, "cell_type": true, "metadata": true, "source": true, "attachments": true}
+	switch cell["cell_type"] {
+	case "markdown", "raw":
+	case "code":
+		allowed["execution_count"] = true
+		allowed["outputs"] = true
+		if _, ok := cell["execution_count"]; !ok {
+			return fmt.Errorf("code cell is missing execution_count")
+		}
+		if count := cell["execution_count"]; count != nil {
+			if n, ok := count.(float64); !ok || n < 0 || n != float64(int(n)) {
+				return fmt.Errorf("execution_count must be null or a non-negative integer")
+			}
+		}
+		outputs, ok := cell["outputs"].([]interface{})
+		if !ok {
+			return fmt.Errorf("code cell must have an outputs array")
+		}
+		for j, out := range outputs {
+			if err := validateOutput(out); err != nil {
+				return fmt.Errorf("output %d: %w", j, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown cell_type %v", cell["cell_type"])
+	}
+
+	for field := range cell {
+		if !allowed[field] {
+			return fmt.Errorf("unexpected field %q in %v cell", field, cell["cell_type"])
+		}
+	}
+	if _, ok := cell["metadata"].(map[string]interface{}); !ok {
+		return fmt.Errorf("metadata must be an object")
+	}
+	if !isMultilineString(cell["source"]) {
+		return fmt.Errorf("source must be a string or an array of strings")
+	}
+
+	if id, present := cell["id"]; present || needsID {
+		s, ok := id.(string)
+		if !ok || !cellIDPattern.MatchString(s) {
+			return fmt.Errorf("id %v does not match %s", id, cellIDPattern)
+		}
+		if ids[s] {
+			return fmt.Errorf("duplicate cell id %q", s)
+		}
+		ids[s] = true
+	}
+	return nil
+}
+
+func validateOutput(raw interface{}) error {
+	out, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("must be an object")
+	}
+	switch out["output_type"] {
+	case "stream":
+		if name := out["name"]; name != "stdout" && name != "stderr" {
+			return fmt.Errorf("stream name must be stdout or stderr, got %v", name)
+		}
+		if !isMultilineString(out["text"]) {
+			return fmt.Errorf("stream text must be a string or an array of strings")
+		}
+	case "execute_result", "display_data":
+		if _, ok := out["data"].(map[string]interface{}); !ok {
+			return fmt.Errorf("%v output must have a data object", out["output_type"])
+		}
+	case "error":
+		for _, field := range []string{"ename", "evalue", "traceback"} {
+			if _, ok := out[field]; !ok {
+				return fmt.Errorf("error output is missing %q", field)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown output_type %v", out["output_type"])
+	}
+	return nil
+}
+
+// isMultilineString reports whether v is a string or an array of strings,
+// the two forms nbformat accepts for source and text fields
+func isMultilineString(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,291 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go-interface-enum-explorer/lessons"
+)
+
+// The schema is nbformat.v4.5.schema.json from the nbformat project
+const schemaFile = "testdata/nbformat.v4.schema.json"
+
+func TestNotebooksMatchSchema(t *testing.T) {
+	schema := loadSchema(t)
+	dir := t.TempDir()
+	all := lessons.All()
+	if err := Notebooks(dir, all); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range all {
+		data, err := os.ReadFile(filepath.Join(dir, l.ID+".ipynb"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s: %v", l.ID, err)
+		}
+		if err := schema.validate(schema.root, doc, "#"); err != nil {
+			t.Errorf("%s: %v", l.ID, err)
+		}
+	}
+}
+
+// The broken notebooks make sure both the schema check of the test and
+// ValidateNotebook reject what they should
+func TestBrokenNotebooks(t *testing.T) {
+	schema := loadSchema(t)
+	for _, tc := range []struct {
+		name string
+		doc  string
+	}{
+		{"wrong nbformat", `{"cells": [], "metadata": {}, "nbformat": 3, "nbformat_minor": 5}`},
+		{"missing cells", `{"metadata": {}, "nbformat": 4, "nbformat_minor": 5}`},
+		{"unknown cell type", `{"cells": [{"id": "a", "cell_type": "prose", "metadata": {}, "source": []}], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`},
+		{"code cell without outputs", `{"cells": [{"id": "a", "cell_type": "code", "metadata": {}, "source": [], "execution_count": null}], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`},
+		{"markdown cell with outputs", `{"cells": [{"id": "a", "cell_type": "markdown", "metadata": {}, "source": [], "outputs": []}], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`},
+		{"bad cell id", `{"cells": [{"id": "a b", "cell_type": "markdown", "metadata": {}, "source": "x"}], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`},
+		{"source not text", `{"cells": [{"id": "a", "cell_type": "markdown", "metadata": {}, "source": [1]}], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`},
+		{"stream without text", `{"cells": [{"id": "a", "cell_type": "code", "metadata": {}, "source": [], "execution_count": 1, "outputs": [{"output_type": "stream", "name": "stdout"}]}], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc interface{}
+			if err := json.Unmarshal([]byte(tc.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := schema.validate(schema.root, doc, "#"); err == nil {
+				t.Error("the schema accepts the notebook")
+			}
+			if err := ValidateNotebook([]byte(tc.doc)); err == nil {
+				t.Error("ValidateNotebook accepts the notebook")
+			}
+		})
+	}
+}
+
+// jsonSchema validates documents against a JSON schema. It knows the
+// draft-04 keywords the nbformat schema uses, and nothing more.
+type jsonSchema struct {
+	root map[string]interface{}
+}
+
+func loadSchema(t *testing.T) *jsonSchema {
+	t.Helper()
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatalf("%s: %v", schemaFile, err)
+	}
+	return &jsonSchema{root: root}
+}
+
+// resolve follows a reference such as "#/definitions/misc/source"
+func (s *jsonSchema) resolve(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	var node interface{} = s.root
+	for _, key := range strings.Split(ref[2:], "/") {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("$ref %q does not resolve", ref)
+		}
+		node = obj[key]
+	}
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("$ref %q does not resolve to a schema", ref)
+	}
+	return obj, nil
+}
+
+// validate checks v against schema; path names v in errors
+func (s *jsonSchema) validate(schema map[string]interface{}, v interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			return err
+		}
+		// In draft-04 a $ref replaces every other keyword next to it
+		return s.validate(target, v, path)
+	}
+
+	if types, ok := schema["type"]; ok && !hasType(types, v) {
+		return fmt.Errorf("%s: %v is not of type %v", path, v, types)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, v, enum)
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		var errs []string
+		for _, sub := range oneOf {
+			if err := s.validate(sub.(map[string]interface{}), v, path); err != nil {
+				errs = append(errs, err.Error())
+			} else {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: matches %d of the oneOf schemas, want 1 (%s)", path, matches, strings.Join(errs, "; "))
+		}
+	}
+
+	switch v := v.(type) {
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			return fmt.Errorf("%s: %v is less than %v", path, v, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			return fmt.Errorf("%s: %v is more than %v", path, v, max)
+		}
+	case string:
+		if min, ok := schema["minLength"].(float64); ok && utf8.RuneCountInString(v) < int(min) {
+			return fmt.Errorf("%s: %q is shorter than %v", path, v, min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && utf8.RuneCountInString(v) > int(max) {
+			return fmt.Errorf("%s: %q is longer than %v", path, v, max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return err
+			}
+			if !re.MatchString(v) {
+				return fmt.Errorf("%s: %q does not match %s", path, v, pattern)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := s.validate(items, item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+		if unique, _ := schema["uniqueItems"].(bool); unique {
+			for i := range v {
+				for j := i + 1; j < len(v); j++ {
+					if reflect.DeepEqual(v[i], v[j]) {
+						return fmt.Errorf("%s: items %d and %d are equal", path, i, j)
+					}
+				}
+			}
+		}
+	case map[string]interface{}:
+		return s.validateObject(schema, v, path)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateObject(schema, v map[string]interface{}, path string) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	patterns, _ := schema["patternProperties"].(map[string]interface{})
+	for name, value := range v {
+		at := path + "/" + name
+		matched := false
+		if sub, ok := properties[name].(map[string]interface{}); ok {
+			matched = true
+			if err := s.validate(sub, value, at); err != nil {
+				return err
+			}
+		}
+		for pattern, sub := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return err
+			}
+			if re.MatchString(name) {
+				matched = true
+				if err := s.validate(sub.(map[string]interface{}), value, at); err != nil {
+					return err
+				}
+			}
+		}
+		if matched {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: property %q is not allowed", path, name)
+			}
+		case map[string]interface{}:
+			if err := s.validate(additional, value, at); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasType reports whether v has the JSON type, or one of the types, given
+// by a schema's "type" keyword
+func hasType(types interface{}, v interface{}) bool {
+	var names []interface{}
+	switch t := types.(type) {
+	case string:
+		names = []interface{}{t}
+	case []interface{}:
+		names = t
+	}
+	for _, name := range names {
+		switch name {
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "number":
+			if _, ok := v.(float64); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := v.(float64); ok && n == float64(int64(n)) {
+				return true
+			}
+		}
+	}
+	return false
+}
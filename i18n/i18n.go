@@ -0,0 +1,212 @@
+// Package i18n translates the explorer's user interface and lessons. Every
+// message has an ID, and each language has a catalog mapping IDs to text.
+// Catalogs are JSON objects stored in locales/<language>.json; English is
+// complete and every other language falls back to it message by message.
+package i18n
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the language of the built-in text and the fallback for
+// messages a catalog does not translate
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	catalogs = make(map[string]map[string]string)
+	current  = DefaultLanguage
+)
+
+func init() {
+	entries, err := fs.ReadDir(locales, "locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		data, err := fs.ReadFile(locales, path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		if err := Load(strings.TrimSuffix(e.Name(), ".json"), data); err != nil {
+			panic(fmt.Sprintf("locales/%s: %v", e.Name(), err))
+		}
+	}
+}
+
+// Load adds the messages of a JSON catalog to a language, replacing
+// messages it already has
+func Load(lang string, data []byte) error {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("catalog must be a JSON object of message IDs to text: %w", err)
+	}
+	lang = normalize(lang)
+	if catalogs[lang] == nil {
+		catalogs[lang] = make(map[string]string)
+	}
+	for id, text := range messages {
+		if text != "" {
+			catalogs[lang][id] = text
+		}
+	}
+	return nil
+}
+
+// LoadFile loads a catalog file whose name, such as de.json, gives its
+// language
+func LoadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	lang := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	if err := Load(lang, data); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
+
+// normalize turns locale names such as "pt_BR.UTF-8" into "pt-br"
+func normalize(lang string) string {
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	return strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+}
+
+// SetLanguage selects the language of T. A regional variant falls back to
+// its base language ("es-MX" uses "es") and unknown languages to English.
+// It returns the language that was selected.
+func SetLanguage(lang string) string {
+	lang = normalize(lang)
+	base, _, _ := strings.Cut(lang, "-")
+	switch {
+	case catalogs[lang] != nil:
+		current = lang
+	case catalogs[base] != nil:
+		current = base
+	default:
+		current = DefaultLanguage
+	}
+	return current
+}
+
+// FromEnvironment returns the language requested by the LC_ALL,
+// LC_MESSAGES or LANG environment variables, or "" when none is set
+func FromEnvironment() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" && v != "C" && v != "POSIX" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Language returns the selected language
+func Language() string {
+	return current
+}
+
+// Languages returns every language with a catalog
+func Languages() []string {
+	var langs []string
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// T returns the message with the given ID in the selected language, or in
+// English when it is not translated. With arguments the message is used as
+// a fmt format.
+func T(id string, args ...interface{}) string {
+	text, ok := catalogs[current][id]
+	if !ok {
+		if text, ok = catalogs[DefaultLanguage][id]; !ok {
+			text = id
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Translation returns the message in the selected language only, without
+// falling back to English. Lessons use it to replace the sections that
+// have been translated and keep their own English text for the rest.
+func Translation(id string) (string, bool) {
+	if current == DefaultLanguage {
+		return "", false
+	}
+	text, ok := catalogs[current][id]
+	return text, ok
+}
+
+// Long messages, such as the sections of a lesson, are edited after they
+// have been translated. A catalog records the English text a translation
+// was made from by storing its Fingerprint under the message ID followed by
+// ".source". When the English changes the fingerprints no longer match and
+// the translation is out of date: TranslationOf ignores it and Untranslated
+// hands the message to translators again. Translations without a
+// fingerprint are never considered out of date.
+
+// Fingerprint returns a short hash of an English message
+func Fingerprint(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:6])
+}
+
+// TranslationOf is like Translation but only returns a translation made
+// from the given English text
+func TranslationOf(id, english string) (string, bool) {
+	text, ok := Translation(id)
+	if !ok || outdated(catalogs[current], id, english) {
+		return "", false
+	}
+	return text, true
+}
+
+// outdated reports whether the catalog's translation of id was made from
+// text other than english
+func outdated(catalog map[string]string, id, english string) bool {
+	source, ok := catalog[id+".source"]
+	return ok && source != Fingerprint(english)
+}
+
+// English returns the English user interface messages
+func English() map[string]string {
+	messages := make(map[string]string, len(catalogs[DefaultLanguage]))
+	for id, text := range catalogs[DefaultLanguage] {
+		messages[id] = text
+	}
+	return messages
+}
+
+// Untranslated returns the messages from source that lang does not
+// translate, or whose translation is out of date, with their English text,
+// ready to be handed to a translator
+func Untranslated(lang string, source map[string]string) map[string]string {
+	catalog := catalogs[normalize(lang)]
+	missing := make(map[string]string)
+	for id, text := range source {
+		if _, ok := catalog[id]; !ok || outdated(catalog, id, text) {
+			missing[id] = text
+		}
+	}
+	return missing
+}
@@ -0,0 +1,127 @@
+// Package inspect shows what an interface value holds: its dynamic type,
+// the two machine words the gc compiler stores for it and whether the value
+// is stored in the data word itself or behind a pointer to a copy.
+//
+// The words are read with unsafe, so the layout they are read with is
+// checked against reflect once, on first use (see Verify). When the check
+// fails, as it would with another compiler or a future change to the
+// runtime, Inspect still reports everything reflect can tell and leaves the
+// words out.
+package inspect
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// Value describes an interface value
+type Value struct {
+	Static  string // the interface type of the variable, such as examples.AssertAnimal
+	Empty   bool   // the interface has no methods (interface{}), so its type word is the dynamic type itself
+	Nil     bool   // the interface holds no type and no value: it equals nil
+	Dynamic string // the type of the value stored, "" for a nil interface
+	Value   string // the stored value in Go syntax
+
+	// NilPointer reports that the stored value is a nil pointer. The
+	// interface has a type, so it does not equal nil: the "typed nil".
+	NilPointer bool
+	// Indirect reports that the data word points to a copy of the value
+	// instead of holding the value itself, which only pointer-shaped types
+	// such as pointers, maps, channels and functions can do
+	Indirect bool
+
+	// Words reports that the words below could be read
+	Words bool
+	// TypeWord is the first word: a pointer to the itab of the interface
+	// and dynamic type for interfaces with methods, or to the dynamic
+	// type's descriptor for interface{}
+	TypeWord uintptr
+	// DataWord is the second word: the value itself for pointer-shaped
+	// types, otherwise a pointer to a copy of the value
+	DataWord uintptr
+	// TypeDescriptor is the dynamic type's descriptor, read from the itab
+	// for interfaces with methods. Values of the same dynamic type share it,
+	// whatever interface they are stored in.
+	TypeDescriptor uintptr
+}
+
+// Inspect describes the interface value in the variable p points to, such
+// as &animal for a variable of type AssertAnimal or &v for an interface{}.
+// It takes a pointer because passing the value itself would convert it to
+// interface{} and lose the interface type of the variable.
+func Inspect(p interface{}) (Value, error) {
+	ptr := reflect.ValueOf(p)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Interface {
+		return Value{}, fmt.Errorf("inspect: expected a pointer to an interface variable, got %T", p)
+	}
+	iface := ptr.Elem()
+
+	v := Value{
+		Static: iface.Type().String(),
+		Empty:  iface.NumMethod() == 0,
+		Nil:    iface.IsNil(),
+	}
+	if !v.Nil {
+		dynamic := iface.Elem()
+		v.Dynamic = dynamic.Type().String()
+		v.Value = fmt.Sprintf("%#v", dynamic.Interface())
+		v.Indirect = !pointerShaped(dynamic.Type())
+		v.NilPointer = dynamic.Kind() == reflect.Ptr && dynamic.IsNil()
+	}
+
+	if Verify() == nil {
+		w := readWords(ptr.UnsafePointer(), v.Empty)
+		v.Words = true
+		v.TypeWord = uintptr(w.typ)
+		v.DataWord = uintptr(w.data)
+		v.TypeDescriptor = uintptr(w.descriptor)
+	}
+	return v, nil
+}
+
+// words are the two words of an interface value as the gc compiler lays
+// them out, plus the type descriptor they lead to
+type words struct {
+	typ, data, descriptor unsafe.Pointer
+}
+
+// readWords reads the interface value at p. For interfaces with methods the
+// type word points to an itab, whose second field is the type descriptor.
+func readWords(p unsafe.Pointer, empty bool) words {
+	pair := (*[2]unsafe.Pointer)(p)
+	w := words{typ: pair[0], data: pair[1], descriptor: pair[0]}
+	if !empty && pair[0] != nil {
+		itab := (*[2]unsafe.Pointer)(pair[0])
+		w.descriptor = itab[1]
+	}
+	return w
+}
+
+// pointerShaped reports whether values of type t fit in the data word
+// itself. These are the types whose values are a single pointer.
+func pointerShaped(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Struct:
+		return t.NumField() == 1 && pointerShaped(t.Field(0).Type)
+	case reflect.Array:
+		return t.Len() == 1 && pointerShaped(t.Elem())
+	}
+	return false
+}
+
+var (
+	verifyOnce sync.Once
+	verifyErr  error
+)
+
+// Verify checks, once, that interface values are laid out the way Inspect
+// reads them, by comparing what it reads for known values with what reflect
+// reports. Inspect only reads the words when this returns nil.
+func Verify() error {
+	verifyOnce.Do(func() { verifyErr = verify() })
+	return verifyErr
+}
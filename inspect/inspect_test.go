@@ -0,0 +1,155 @@
+package inspect
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// speaker is an interface with methods, like the lessons' AssertAnimal
+type speaker interface {
+	Speak() string
+}
+
+type dog struct {
+	Name  string
+	Years int
+}
+
+func (d dog) Speak() string { return d.Name + " says Woof" }
+
+type robot struct{ Model string }
+
+func (r *robot) Speak() string { return "Beep" }
+
+func TestVerify(t *testing.T) {
+	if err := Verify(); err != nil {
+		t.Fatalf("the interface layout does not match this Go version: %v", err)
+	}
+}
+
+func TestNilInterface(t *testing.T) {
+	var s speaker
+	v := mustInspect(t, &s)
+	if !v.Nil || v.NilPointer || v.Dynamic != "" {
+		t.Errorf("nil interface: got %+v", v)
+	}
+	if v.Static != "inspect.speaker" || v.Empty {
+		t.Errorf("nil interface: static type %s, empty %v", v.Static, v.Empty)
+	}
+	if !v.Words || v.TypeWord != 0 || v.DataWord != 0 {
+		t.Errorf("nil interface: words %v, %#x, %#x, want two zero words", v.Words, v.TypeWord, v.DataWord)
+	}
+}
+
+func TestTypedNil(t *testing.T) {
+	var r *robot
+	var s speaker = r
+	v := mustInspect(t, &s)
+	if v.Nil {
+		t.Fatal("an interface holding a nil *robot reports that it is nil")
+	}
+	if !v.NilPointer || v.Dynamic != "*inspect.robot" || v.Indirect {
+		t.Errorf("typed nil: got %+v", v)
+	}
+	if v.TypeWord == 0 || v.DataWord != 0 {
+		t.Errorf("typed nil: type word %#x, data word %#x, want a type and a nil data word", v.TypeWord, v.DataWord)
+	}
+
+	var e interface{} = r
+	if v := mustInspect(t, &e); v.Nil || !v.NilPointer || !v.Empty {
+		t.Errorf("typed nil in interface{}: got %+v", v)
+	}
+}
+
+func TestPointerShaped(t *testing.T) {
+	r := &robot{Model: "R2"}
+	var s speaker = r
+	v := mustInspect(t, &s)
+	if v.Indirect || v.NilPointer {
+		t.Errorf("*robot: got %+v", v)
+	}
+	if v.DataWord != uintptr(unsafe.Pointer(r)) {
+		t.Errorf("*robot: data word %#x, want the pointer %p", v.DataWord, r)
+	}
+
+	// Maps and channels are pointers too
+	m := map[string]int{"a": 1}
+	ch := make(chan int)
+	for _, value := range []interface{}{m, ch} {
+		e := value
+		v := mustInspect(t, &e)
+		if v.Indirect || v.DataWord != reflect.ValueOf(value).Pointer() {
+			t.Errorf("%T: indirect %v, data word %#x, want %#x in the data word", value, v.Indirect, v.DataWord, reflect.ValueOf(value).Pointer())
+		}
+	}
+	// So are functions and structs and arrays of a single pointer
+	for _, value := range []interface{}{func() {}, struct{ p *robot }{r}, [1]*robot{r}} {
+		e := value
+		if v := mustInspect(t, &e); v.Indirect {
+			t.Errorf("%T is stored indirectly, want in the data word", value)
+		}
+	}
+}
+
+func TestIndirect(t *testing.T) {
+	d := dog{Name: "Rex", Years: 3}
+	var s speaker = d
+	v := mustInspect(t, &s)
+	if !v.Indirect || v.NilPointer || v.Dynamic != "inspect.dog" {
+		t.Errorf("dog: got %+v", v)
+	}
+	if v.DataWord == 0 || v.DataWord == uintptr(unsafe.Pointer(&d)) {
+		t.Errorf("dog: data word %#x, want a pointer to a copy, not to the variable", v.DataWord)
+	}
+	if want := fmt.Sprintf("%#v", d); v.Value != want {
+		t.Errorf("dog: value %s, want %s", v.Value, want)
+	}
+
+	for _, value := range []interface{}{42, "text", 3.5, []int{1}, [2]*robot{}, struct{ a, b *robot }{}} {
+		e := value
+		if v := mustInspect(t, &e); !v.Indirect {
+			t.Errorf("%T is stored in the data word, want indirectly", value)
+		}
+	}
+}
+
+// The type word of interface{} is the type descriptor; an interface with
+// methods points to an itab, which leads to the same descriptor
+func TestTypeDescriptor(t *testing.T) {
+	d := dog{Name: "Rex"}
+	var s speaker = d
+	var e interface{} = d
+	var other interface{} = dog{Name: "Fido"}
+	sv, ev, ov := mustInspect(t, &s), mustInspect(t, &e), mustInspect(t, &other)
+
+	if sv.TypeDescriptor != ev.TypeWord || ev.TypeDescriptor != ev.TypeWord {
+		t.Errorf("descriptors differ: speaker %#x, interface{} %#x", sv.TypeDescriptor, ev.TypeWord)
+	}
+	if sv.TypeWord == ev.TypeWord {
+		t.Error("the type word of speaker is the type descriptor, want an itab")
+	}
+	if ov.TypeWord != ev.TypeWord {
+		t.Error("two dogs in interface{} have different type words")
+	}
+}
+
+func TestInspectNeedsAPointer(t *testing.T) {
+	var s speaker = dog{}
+	var nilPointer *speaker
+	for _, p := range []interface{}{s, nil, 42, nilPointer, new(int)} {
+		if _, err := Inspect(p); err == nil {
+			t.Errorf("Inspect(%T) does not fail", p)
+		}
+	}
+}
+
+func mustInspect(t *testing.T, p interface{}) Value {
+	t.Helper()
+	v, err := Inspect(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
@@ -0,0 +1,68 @@
+package inspect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// layoutProbe is a type with a method, so it can be stored in an interface
+// with methods as well as in interface{}
+type layoutProbe struct {
+	a, b int
+}
+
+func (p layoutProbe) String() string { return fmt.Sprint(p.a, p.b) }
+
+// verify checks each assumption Inspect makes about the layout of
+// interface values and returns the first one that does not hold
+func verify() error {
+	if runtime.Compiler != "gc" {
+		return fmt.Errorf("inspect: interface layout is only known for the gc compiler, not %s", runtime.Compiler)
+	}
+	if size := unsafe.Sizeof(interface{}(nil)); size != 2*unsafe.Sizeof(uintptr(0)) {
+		return fmt.Errorf("inspect: an interface value is %d bytes, not two words", size)
+	}
+
+	var none interface{}
+	if w := readWords(unsafe.Pointer(&none), true); w.typ != nil || w.data != nil {
+		return errors.New("inspect: a nil interface does not have two nil words")
+	}
+
+	// A pointer is stored in the data word itself
+	probe := &layoutProbe{a: 1, b: 2}
+	var empty interface{} = probe
+	emptyWords := readWords(unsafe.Pointer(&empty), true)
+	if emptyWords.data != unsafe.Pointer(probe) {
+		return errors.New("inspect: the data word of interface{} does not hold a stored pointer")
+	}
+	var stringer fmt.Stringer = probe
+	stringerWords := readWords(unsafe.Pointer(&stringer), false)
+	if stringerWords.data != unsafe.Pointer(probe) {
+		return errors.New("inspect: the data word of an interface with methods does not hold a stored pointer")
+	}
+
+	// The type word of interface{} and the itab of an interface with
+	// methods lead to the same type descriptor
+	if emptyWords.typ == nil || stringerWords.descriptor != emptyWords.typ {
+		return errors.New("inspect: the itab does not lead to the type descriptor of interface{}")
+	}
+	var other interface{} = &layoutProbe{}
+	if readWords(unsafe.Pointer(&other), true).typ != emptyWords.typ {
+		return errors.New("inspect: values of the same type do not share a type word")
+	}
+
+	// Other values are stored behind a pointer to a copy
+	value := layoutProbe{a: 3, b: 4}
+	var boxed interface{} = value
+	boxedWords := readWords(unsafe.Pointer(&boxed), true)
+	if boxedWords.data == nil || *(*layoutProbe)(boxedWords.data) != value {
+		return errors.New("inspect: the data word of a struct does not point to a copy of it")
+	}
+	if pointerShaped(reflect.TypeOf(value)) || !pointerShaped(reflect.TypeOf(probe)) {
+		return errors.New("inspect: pointer-shaped types are not recognized")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package lessons
+
+import (
+	"embed"
+
+	"go-interface-enum-explorer/examples"
+)
+
+// content holds the lessons written in the Markdown lesson format
+//
+//go:embed content/*.md
+var content embed.FS
+
+// The lessons that ship with the tutorial. Each lesson lists the lessons
+// it builds on; the tutorial order is derived from those prerequisites.
+func init() {
+	// Interfaces
+	Register(&Lesson{ID: "basic-interfaces", Title: "Basic Interfaces", Category: "interfaces", Difficulty: "beginner", Run: examples.BasicInterfaces})
+	Register(&Lesson{ID: "interface-implementation", Title: "Interface Implementation", Category: "interfaces", Difficulty: "beginner", Prerequisites: []string{"basic-interfaces"}, Run: examples.InterfaceImplementation})
+	Register(&Lesson{ID: "writer-pipelines", Title: "Writer Pipelines", Category: "interfaces", Difficulty: "intermediate", Prerequisites: []string{"interface-implementation"}, Run: examples.WriterPipelines})
+	Register(&Lesson{ID: "empty-interface", Title: "Empty Interface", Category: "interfaces", Difficulty: "intermediate", Prerequisites: []string{"basic-interfaces"}, Run: examples.EmptyInterface})
+	Register(&Lesson{ID: "type-assertion", Title: "Type Assertion", Category: "interfaces", Difficulty: "intermediate", Prerequisites: []string{"interface-implementation", "empty-interface"}, Run: examples.TypeAssertion})
+	Register(&Lesson{ID: "interface-internals", Title: "Interface Internals", Category: "interfaces", Difficulty: "advanced", Prerequisites: []string{"type-assertion"}, Run: examples.InterfaceInternals})
+	Register(&Lesson{ID: "interface-composition", Title: "Interface Composition", Category: "interfaces", Difficulty: "advanced", Prerequisites: []string{"interface-implementation"}, Run: examples.InterfaceComposition})
+	Register(&Lesson{ID: "typed-nil", Title: "Typed Nil Interfaces", Category: "interfaces", Difficulty: "advanced", Prerequisites: []string{"interface-composition", "interface-internals"}, Run: examples.TypedNil})
+	Register(&Lesson{ID: "sealed-interfaces", Title: "Sealed Interfaces", Category: "interfaces", Difficulty: "advanced", Prerequisites: []string{"type-assertion", "interface-composition"}, Run: examples.SealedInterfaces})
+	Register(&Lesson{ID: "json-interfaces", Title: "Interfaces and JSON", Category: "interfaces", Difficulty: "intermediate", Prerequisites: []string{"type-assertion"}, Run: examples.JSONInterfaces})
+
+	// Enums
+	Register(&Lesson{ID: "basic-enums", Title: "Basic Enums", Category: "enums", Difficulty: "beginner", Run: examples.BasicEnums})
+	Register(&Lesson{ID: "iota-enums", Title: "Iota Enums", Category: "enums", Difficulty: "beginner", Prerequisites: []string{"basic-enums"}, Run: examples.IotaEnums})
+	Register(&Lesson{ID: "string-enums", Title: "String Enums", Category: "enums", Difficulty: "intermediate", Prerequisites: []string{"iota-enums", "stringer-interface"}, Run: examples.StringEnums})
+	Register(&Lesson{ID: "behavior-enums", Title: "Behavior Enums", Category: "enums", Difficulty: "advanced", Prerequisites: []string{"string-enums", "interface-implementation"}, Run: examples.BehaviorEnums})
+
+	// A broken embedded lesson or prerequisite graph is a bug in the binary
+	// itself
+	if err := LoadFS(content, "content"); err != nil {
+		panic(err)
+	}
+	if err := Validate(); err != nil {
+		panic(err)
+	}
+}
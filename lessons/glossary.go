@@ -0,0 +1,238 @@
+package lessons
+
+import (
+	"sort"
+	"strings"
+
+	"go-interface-enum-explorer/utils"
+)
+
+// Term is a glossary entry: a concept the lessons refer to by name
+type Term struct {
+	ID         string
+	Name       string
+	Aliases    []string // other spellings recognized in lesson text
+	Definition string
+	Example    string
+}
+
+// Glossary lists the terms used across the lessons, in alphabetical order
+var Glossary = []*Term{
+	{
+		ID:      "comma-ok",
+		Name:    "comma ok",
+		Aliases: []string{"comma-ok"},
+		Definition: "The two-value form of a type assertion, map lookup or channel receive. " +
+			"The second value reports whether the operation succeeded instead of panicking or returning a zero value silently.",
+		Example: "if s, ok := v.(string); ok {\n\tfmt.Println(\"got a string:\", s)\n}",
+	},
+	{
+		ID:         "empty-interface",
+		Name:       "empty interface",
+		Aliases:    []string{"empty interfaces", "interface{}"},
+		Definition: "An interface with no methods, written interface{} or any. Every type satisfies it, so it can hold a value of any type, at the cost of compile-time type checking.",
+		Example:    "var anything interface{}\nanything = 42\nanything = \"now a string\"",
+	},
+	{
+		ID:         "escape-analysis",
+		Name:       "escape analysis",
+		Aliases:    []string{"escapes to heap", "heap allocation", "heap allocations"},
+		Definition: "The compiler's decision, for every value, whether it can live on the stack or must be allocated on the heap because it outlives its function. Storing a value in an interface often moves it to the heap; go build -gcflags=-m shows the decisions.",
+		Example:    "var v interface{} = n // n escapes to heap\nsum := n + 1           // sum stays on the stack",
+	},
+	{
+		ID:         "fmt-stringer",
+		Name:       "fmt.Stringer",
+		Aliases:    []string{"Stringer"},
+		Definition: "The interface type Stringer interface { String() string } from package fmt. The fmt functions call String when they print a value that implements it.",
+		Example:    "func (d Direction) String() string {\n\treturn [...]string{\"North\", \"East\", \"South\", \"West\"}[d]\n}",
+	},
+	{
+		ID:         "implicit-implementation",
+		Name:       "implicit implementation",
+		Aliases:    []string{"implicitly", "implicit"},
+		Definition: "A Go type implements an interface simply by having all of its methods. There is no implements keyword and the type does not need to know the interface exists.",
+		Example:    "type Speaker interface{ Speak() string }\n\ntype Dog struct{}\n\n// Dog is a Speaker; nothing else needs to be declared\nfunc (Dog) Speak() string { return \"Woof\" }",
+	},
+	{
+		ID:         "interface-composition",
+		Name:       "interface composition",
+		Aliases:    []string{"embedded interfaces", "embedded interface"},
+		Definition: "Building an interface by embedding other interfaces in it. The result requires every method of the embedded interfaces.",
+		Example:    "type ReadWriter interface {\n\tReader\n\tWriter\n}",
+	},
+	{
+		ID:         "interface-segregation",
+		Name:       "interface segregation",
+		Aliases:    []string{"interface segregation principle"},
+		Definition: "The principle that code should not depend on methods it does not use. In Go it leads to small interfaces, often with a single method, that are composed when more is needed.",
+		Example:    "// Accept only what the function needs\nfunc Save(w io.Writer, data []byte) error {\n\t_, err := w.Write(data)\n\treturn err\n}",
+	},
+	{
+		ID:         "iota",
+		Name:       "iota",
+		Definition: "A predeclared identifier that counts the constant specifications in a const block, starting at 0. It is the usual way to number the values of an enum.",
+		Example:    "const (\n\tNorth Direction = iota // 0\n\tEast                   // 1\n\tSouth                  // 2\n)",
+	},
+	{
+		ID:         "method-set",
+		Name:       "method set",
+		Aliases:    []string{"method sets"},
+		Definition: "The methods that can be called on a type, which decide the interfaces it implements. The method set of *T includes the methods with a value receiver T, but the method set of T does not include methods with a pointer receiver.",
+		Example:    "func (c *Counter) Inc() { c.n++ }\n\nvar _ Incrementer = &Counter{} // ok\n// var _ Incrementer = Counter{} // does not compile",
+	},
+	{
+		ID:         "polymorphism",
+		Name:       "polymorphism",
+		Definition: "Treating values of different types through one interface, so the same code works with each of them and the method that runs depends on the dynamic type.",
+		Example:    "shapes := []Shape{Circle{Radius: 1}, Rectangle{Width: 2, Height: 3}}\nfor _, s := range shapes {\n\tfmt.Println(s.Area())\n}",
+	},
+	{
+		ID:         "type-assertion",
+		Name:       "type assertion",
+		Aliases:    []string{"type assertions"},
+		Definition: "The expression x.(T), which extracts the value of type T stored in the interface value x. It panics when x holds another type unless the comma ok form is used.",
+		Example:    "var v interface{} = \"hello\"\ns := v.(string)",
+	},
+	{
+		ID:         "type-safety",
+		Name:       "type safety",
+		Definition: "The guarantee that the compiler rejects values of the wrong type. Giving enum constants their own named type means a plain int or another enum cannot be passed by mistake.",
+		Example:    "type Weekday int\n\nfunc Schedule(d Weekday) {}\n\n// Schedule(Color(1)) does not compile",
+	},
+	{
+		ID:         "type-switch",
+		Name:       "type switch",
+		Aliases:    []string{"type switches"},
+		Definition: "A switch on the dynamic type of an interface value. Each case names a type, and inside it the variable has that type.",
+		Example:    "switch v := x.(type) {\ncase int:\n\tfmt.Println(\"int\", v+1)\ncase string:\n\tfmt.Println(\"string\", len(v))\n}",
+	},
+	{
+		ID:         "typed-nil",
+		Name:       "typed nil",
+		Aliases:    []string{"nil interface"},
+		Definition: "An interface value holding a nil pointer. It has a dynamic type, so it does not equal nil, unlike an interface that holds nothing at all.",
+		Example:    "var p *MyError\nvar err error = p\nfmt.Println(err == nil) // false",
+	},
+}
+
+// LookupTerm finds a glossary term by ID, name or alias, ignoring case
+func LookupTerm(name string) *Term {
+	name = strings.TrimSpace(name)
+	for _, t := range Glossary {
+		if strings.EqualFold(t.ID, name) || strings.EqualFold(t.Name, name) {
+			return t
+		}
+		for _, alias := range t.Aliases {
+			if strings.EqualFold(alias, name) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// Lessons returns the lessons whose explanation, code or takeaways use the
+// term, in tutorial order
+func (t *Term) Lessons() []*Lesson {
+	var using []*Lesson
+	for _, l := range All() {
+		for _, kind := range []utils.SectionKind{utils.SectionExplanation, utils.SectionCode, utils.SectionTakeaways} {
+			if containsTerm(l.Section(kind), t) {
+				using = append(using, l)
+				break
+			}
+		}
+	}
+	return using
+}
+
+func containsTerm(text string, t *Term) bool {
+	for _, m := range FindTerms(text) {
+		if m.Term == t {
+			return true
+		}
+	}
+	return false
+}
+
+// TermMatch is an occurrence of a glossary term in a text, from byte
+// offset Start up to End
+type TermMatch struct {
+	Term       *Term
+	Start, End int
+}
+
+// spelling is one way of writing a term
+type spelling struct {
+	text string
+	term *Term
+}
+
+// spellings returns every name and alias, longest first, so that
+// "interface segregation principle" wins over "interface segregation"
+func spellings() []spelling {
+	var all []spelling
+	for _, t := range Glossary {
+		all = append(all, spelling{t.Name, t})
+		for _, alias := range t.Aliases {
+			all = append(all, spelling{alias, t})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return len(all[i].text) > len(all[j].text)
+	})
+	return all
+}
+
+// FindTerms returns the glossary terms mentioned in text, in order and
+// without overlaps. Matching ignores case and only accepts whole words.
+func FindTerms(text string) []TermMatch {
+	lower := strings.ToLower(text)
+	candidates := spellings()
+
+	var matches []TermMatch
+	for i := 0; i < len(lower); {
+		if i > 0 && isWordByte(lower[i-1]) && isWordByte(lower[i]) {
+			i++
+			continue
+		}
+		found := false
+		for _, s := range candidates {
+			word := strings.ToLower(s.text)
+			end := i + len(word)
+			if !strings.HasPrefix(lower[i:], word) {
+				continue
+			}
+			if end < len(lower) && isWordByte(lower[end]) && isWordByte(word[len(word)-1]) {
+				continue
+			}
+			matches = append(matches, TermMatch{Term: s.term, Start: i, End: end})
+			i = end
+			found = true
+			break
+		}
+		if !found {
+			i++
+		}
+	}
+	return matches
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// MarkTerms rewrites every glossary term in text with mark, which receives
+// the term and the text as it was written
+func MarkTerms(text string, mark func(t *Term, written string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range FindTerms(text) {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(mark(m.Term, text[m.Start:m.End]))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -0,0 +1,182 @@
+package lessons
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate checks the prerequisite graph: every prerequisite must name a
+// registered lesson and the graph must not contain cycles
+func Validate() error {
+	byID := make(map[string]*Lesson, len(registry))
+	for _, l := range registry {
+		byID[l.ID] = l
+	}
+	for _, l := range registry {
+		for _, p := range l.Prerequisites {
+			if byID[p] == nil {
+				return fmt.Errorf("lesson %s: unknown prerequisite %q", l.ID, p)
+			}
+			if p == l.ID {
+				return fmt.Errorf("lesson %s lists itself as a prerequisite", l.ID)
+			}
+		}
+	}
+	if cycle := findCycle(byID); cycle != nil {
+		return fmt.Errorf("prerequisite cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle returns the IDs along a prerequisite cycle, starting and ending
+// with the same lesson, or nil when the graph is acyclic
+func findCycle(byID map[string]*Lesson) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycle []string
+
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, p := range byID[id].Prerequisites {
+			switch state[p] {
+			case visiting:
+				for i, s := range stack {
+					if s == p {
+						cycle = append(append([]string{}, stack[i:]...), p)
+						break
+					}
+				}
+				return true
+			case unvisited:
+				if byID[p] != nil && visit(p) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		return false
+	}
+
+	for _, l := range registry {
+		if state[l.ID] == unvisited && visit(l.ID) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// rank orders lessons that are ready at the same time: by category, then by
+// registration order
+func rank(l *Lesson) (int, int) {
+	category := len(Categories)
+	for i, c := range Categories {
+		if c.ID == l.Category {
+			category = i
+			break
+		}
+	}
+	for i, r := range registry {
+		if r == l {
+			return category, i
+		}
+	}
+	return category, len(registry)
+}
+
+func before(a, b *Lesson) bool {
+	ac, ai := rank(a)
+	bc, bi := rank(b)
+	if ac != bc {
+		return ac < bc
+	}
+	return ai < bi
+}
+
+// tutorialOrder sorts lessons topologically so every lesson comes after its
+// prerequisites. Among lessons that are ready at the same time the one from
+// the earlier category, then the earlier registered one, goes first, which
+// keeps interfaces ahead of enums. Prerequisites outside the given set are
+// ignored.
+func tutorialOrder(list []*Lesson) []*Lesson {
+	included := make(map[string]bool, len(list))
+	for _, l := range list {
+		included[l.ID] = true
+	}
+
+	placed := make(map[string]bool, len(list))
+	ordered := make([]*Lesson, 0, len(list))
+	for len(ordered) < len(list) {
+		var next *Lesson
+		for _, l := range list {
+			if placed[l.ID] || !ready(l, included, placed) {
+				continue
+			}
+			if next == nil || before(l, next) {
+				next = l
+			}
+		}
+		if next == nil {
+			// A cycle; Validate reports these, so keep whatever is left in
+			// rank order rather than losing lessons
+			for _, l := range list {
+				if !placed[l.ID] {
+					ordered = append(ordered, l)
+					placed[l.ID] = true
+				}
+			}
+			break
+		}
+		ordered = append(ordered, next)
+		placed[next.ID] = true
+	}
+	return ordered
+}
+
+func ready(l *Lesson, included, placed map[string]bool) bool {
+	for _, p := range l.Prerequisites {
+		if included[p] && !placed[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// PathTo plans the minimal learning path to a lesson: the lesson itself and
+// everything it depends on, directly or indirectly, in tutorial order
+func PathTo(id string) ([]*Lesson, error) {
+	target := Lookup(id)
+	if target == nil {
+		return nil, fmt.Errorf("no lesson %q", id)
+	}
+
+	needed := make(map[string]bool)
+	var collect func(l *Lesson)
+	collect = func(l *Lesson) {
+		if needed[l.ID] {
+			return
+		}
+		needed[l.ID] = true
+		for _, p := range l.Prerequisites {
+			if dep := Lookup(p); dep != nil {
+				collect(dep)
+			}
+		}
+	}
+	collect(target)
+
+	var path []*Lesson
+	for _, l := range All() {
+		if needed[l.ID] {
+			path = append(path, l)
+		}
+	}
+	return path, nil
+}
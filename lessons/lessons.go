@@ -0,0 +1,133 @@
+// Package lessons is the registry of every lesson in the tutorial. The
+// terminal UI and the exporters all read lessons from here.
+package lessons
+
+import (
+	"strings"
+
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/utils"
+)
+
+// Lesson is one topic of the tutorial. Lessons written in Go have a Run
+// function whose printed output is captured into sections; lessons loaded
+// from Markdown files carry their sections directly.
+type Lesson struct {
+	ID            string
+	Title         string
+	Category      string
+	Difficulty    string
+	Prerequisites []string
+	Run           func()
+
+	// Source is the file a Markdown lesson was loaded from
+	Source string
+
+	sections []utils.Section
+}
+
+// Difficulty levels a lesson can declare
+var Difficulties = []string{"beginner", "intermediate", "advanced"}
+
+// Category groups related lessons
+type Category struct {
+	ID    string
+	Title string
+}
+
+// Categories in the order they are taught
+var Categories = []Category{
+	{ID: "interfaces", Title: "Interfaces"},
+	{ID: "enums", Title: "Enums"},
+}
+
+// registry holds every lesson in the order it was registered
+var registry []*Lesson
+
+// Register adds a lesson to the end of its category. A lesson with the ID of
+// an existing one replaces it, which lets instructors override built-in
+// lessons from their own lesson directory.
+func Register(l *Lesson) {
+	for i, existing := range registry {
+		if existing.ID == l.ID {
+			registry[i] = l
+			return
+		}
+	}
+	registry = append(registry, l)
+}
+
+// All returns every lesson in tutorial order: each lesson comes after its
+// prerequisites, earlier categories first
+func All() []*Lesson {
+	return tutorialOrder(registry)
+}
+
+// InCategory returns the lessons of one category in tutorial order
+func InCategory(category string) []*Lesson {
+	var list []*Lesson
+	for _, l := range All() {
+		if l.Category == category {
+			list = append(list, l)
+		}
+	}
+	return list
+}
+
+// Lookup finds a lesson by ID or by title, ignoring case
+func Lookup(name string) *Lesson {
+	for _, l := range registry {
+		if strings.EqualFold(l.ID, name) || strings.EqualFold(l.Title, name) {
+			return l
+		}
+	}
+	return nil
+}
+
+// CategoryTitle returns the display title of a category in the selected
+// language
+func CategoryTitle(id string) string {
+	if title, ok := i18n.Translation("category." + id); ok {
+		return title
+	}
+	for _, c := range Categories {
+		if c.ID == id {
+			return c.Title
+		}
+	}
+	return id
+}
+
+// Sections runs the lesson once and returns what it printed, split into
+// sections. Later calls return the cached result. Sections translated into
+// the selected language replace the English ones.
+func (l *Lesson) Sections() []utils.Section {
+	if l.sections == nil && l.Run != nil {
+		l.sections = utils.Capture(l.Run)
+	}
+	return l.localize(l.sections)
+}
+
+// Section returns the text of the first section of the given kind
+func (l *Lesson) Section(kind utils.SectionKind) string {
+	for _, s := range l.Sections() {
+		if s.Kind == kind {
+			return s.Text
+		}
+	}
+	return ""
+}
+
+// Show renders the lesson in the terminal under the given heading, with
+// glossary terms highlighted in the explanation
+func (l *Lesson) Show(heading string) {
+	sections := append([]utils.Section(nil), l.Sections()...)
+	for i, s := range sections {
+		if s.Kind == utils.SectionExplanation {
+			sections[i].Text = MarkTerms(s.Text, func(_ *Term, written string) string {
+				return utils.Colorize(utils.ColorCyan, written)
+			})
+		}
+	}
+	utils.RenderLesson(heading, utils.ColorYellow, sections).Show()
+}
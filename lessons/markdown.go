@@ -0,0 +1,300 @@
+package lessons
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-interface-enum-explorer/utils"
+)
+
+// A Markdown lesson starts with front matter between two "---" lines,
+// followed by fenced sections:
+//
+//	---
+//	id: stringer-interface
+//	title: The Stringer Interface
+//	category: interfaces
+//	difficulty: beginner
+//	prerequisites: [basic-interfaces, interface-implementation]
+//	---
+//
+//	::: explanation
+//	Text shown before the code.
+//	:::
+//
+//	::: code
+//	```go
+//	func main() { ... }
+//	```
+//	:::
+//
+//	::: output
+//	What the code prints.
+//	:::
+//
+//	::: takeaways
+//	- One point per line
+//	:::
+//
+// A code line can carry a numbered callout by ending its comment with the
+// number in angle brackets, such as "// <1>", and the explanation and
+// takeaways point at that line by writing <1>. See utils.StripCallouts.
+
+// sectionNames maps the names accepted after ":::" to section kinds
+var sectionNames = map[string]utils.SectionKind{
+	"explanation":     utils.SectionExplanation,
+	"code":            utils.SectionCode,
+	"output":          utils.SectionOutput,
+	"expected output": utils.SectionOutput,
+	"expected-output": utils.SectionOutput,
+	"takeaways":       utils.SectionTakeaways,
+	"key takeaways":   utils.SectionTakeaways,
+}
+
+var lessonIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ParseMarkdown reads a lesson written in the Markdown lesson format. The
+// source name is only used in error messages.
+func ParseMarkdown(data []byte, source string) (*Lesson, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, fmt.Errorf("%s:1: lesson must start with front matter (---)", source)
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("%s: front matter is not closed with ---", source)
+	}
+
+	lesson := &Lesson{Source: source}
+	if err := parseFrontMatter(lesson, lines[1:end], source); err != nil {
+		return nil, err
+	}
+	sections, err := parseSections(lines[end+1:], end+2, source)
+	if err != nil {
+		return nil, err
+	}
+	lesson.sections = sections
+	return lesson, nil
+}
+
+func parseFrontMatter(l *Lesson, lines []string, source string) error {
+	var listKey string
+	for i, line := range lines {
+		lineNo := i + 2
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// "- item" lines continue a list started by "key:" with no value
+		if strings.HasPrefix(trimmed, "- ") {
+			if listKey != "prerequisites" {
+				return fmt.Errorf("%s:%d: unexpected list item", source, lineNo)
+			}
+			l.Prerequisites = append(l.Prerequisites, unquote(strings.TrimPrefix(trimmed, "- ")))
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected \"key: value\"", source, lineNo)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		listKey = ""
+
+		switch key {
+		case "id":
+			l.ID = unquote(value)
+		case "title":
+			l.Title = unquote(value)
+		case "category":
+			l.Category = unquote(value)
+		case "difficulty":
+			l.Difficulty = strings.ToLower(unquote(value))
+		case "prerequisites":
+			if value == "" {
+				listKey = key
+				continue
+			}
+			l.Prerequisites = parseList(value)
+		default:
+			return fmt.Errorf("%s:%d: unknown front matter key %q", source, lineNo, key)
+		}
+	}
+
+	switch {
+	case l.ID == "":
+		return fmt.Errorf("%s: front matter is missing id", source)
+	case !lessonIDPattern.MatchString(l.ID):
+		return fmt.Errorf("%s: id %q must be lowercase words separated by dashes", source, l.ID)
+	case l.Title == "":
+		return fmt.Errorf("%s: front matter is missing title", source)
+	case l.Category == "":
+		return fmt.Errorf("%s: front matter is missing category", source)
+	}
+	if !knownCategory(l.Category) {
+		return fmt.Errorf("%s: unknown category %q", source, l.Category)
+	}
+	if l.Difficulty != "" && !contains(Difficulties, l.Difficulty) {
+		return fmt.Errorf("%s: difficulty must be one of %s", source, strings.Join(Difficulties, ", "))
+	}
+	return nil
+}
+
+// parseList reads an inline list such as "[a, b]" or "a, b"
+func parseList(value string) []string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func parseSections(lines []string, firstLine int, source string) ([]utils.Section, error) {
+	var sections []utils.Section
+	var current *utils.Section
+	var body []string
+	inFence := false
+	openedAt := 0
+
+	for i, line := range lines {
+		lineNo := firstLine + i
+		trimmed := strings.TrimSpace(line)
+
+		if current == nil {
+			if trimmed == "" {
+				continue
+			}
+			if !strings.HasPrefix(trimmed, ":::") {
+				return nil, fmt.Errorf("%s:%d: text outside of a ::: section", source, lineNo)
+			}
+			name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(trimmed, ":::")))
+			kind, ok := sectionNames[name]
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: unknown section %q", source, lineNo, name)
+			}
+			current = &utils.Section{Kind: kind}
+			body = nil
+			openedAt = lineNo
+			continue
+		}
+
+		// A ::: line inside a code fence belongs to the code
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if trimmed == ":::" && !inFence {
+			current.Text = sectionText(current.Kind, body)
+			sections = append(sections, *current)
+			current = nil
+			continue
+		}
+		body = append(body, line)
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%s:%d: section is not closed with :::", source, openedAt)
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("%s: lesson has no sections", source)
+	}
+	return sections, nil
+}
+
+// sectionText joins the lines of a section. Code and output may be wrapped
+// in a Markdown code fence so the file previews nicely; the fence is removed.
+func sectionText(kind utils.SectionKind, lines []string) string {
+	text := strings.Trim(strings.Join(lines, "\n"), "\n")
+	if kind != utils.SectionCode && kind != utils.SectionOutput {
+		return text
+	}
+	if strings.HasPrefix(text, "```") && strings.HasSuffix(text, "```") {
+		if start := strings.Index(text, "\n"); start >= 0 {
+			text = strings.Trim(text[start:len(text)-3], "\n")
+		}
+	}
+	return text
+}
+
+// LoadFS registers every .md lesson in dir of fsys, in file name order
+func LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		file := path.Join(dir, name)
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		lesson, err := ParseMarkdown(data, file)
+		if err != nil {
+			return err
+		}
+		Register(lesson)
+	}
+	return nil
+}
+
+// LoadDir registers every .md lesson in a directory on disk. Lessons with
+// the ID of an already registered lesson replace it.
+func LoadDir(dir string) error {
+	if err := LoadFS(os.DirFS(dir), "."); err != nil {
+		return fmt.Errorf("loading lessons from %s: %w", dir, err)
+	}
+	if err := Validate(); err != nil {
+		return fmt.Errorf("loading lessons from %s: %w", dir, err)
+	}
+	return nil
+}
+
+func knownCategory(id string) bool {
+	for _, c := range Categories {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
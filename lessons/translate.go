@@ -0,0 +1,84 @@
+package lessons
+
+import (
+	"fmt"
+
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/utils"
+)
+
+// Lesson text is translated through message catalogs like the rest of the
+// interface. The message IDs are
+//
+//	lesson.<id>.title
+//	lesson.<id>.<section>      explanation, code, output or takeaways
+//	lesson.<id>.<section>.<n>  the nth section of that kind, from 2 on
+//	category.<id>
+//
+// Every section is translated on its own, so a catalog can translate the
+// explanation and takeaways of a lesson and leave its code in English. A
+// section whose English text has changed since it was translated, as told
+// by the fingerprint stored under lesson.<id>.<section>.source, is shown
+// in English until the translation is brought up to date.
+
+// DisplayTitle returns the title of the lesson in the selected language
+func (l *Lesson) DisplayTitle() string {
+	if title, ok := i18n.Translation("lesson." + l.ID + ".title"); ok {
+		return title
+	}
+	return l.Title
+}
+
+// sectionIDs returns the message ID of every section, in order
+func (l *Lesson) sectionIDs(sections []utils.Section) []string {
+	seen := make(map[utils.SectionKind]int)
+	ids := make([]string, len(sections))
+	for i, s := range sections {
+		seen[s.Kind]++
+		ids[i] = "lesson." + l.ID + "." + s.Kind.Name()
+		if n := seen[s.Kind]; n > 1 {
+			ids[i] += fmt.Sprintf(".%d", n)
+		}
+	}
+	return ids
+}
+
+// localize replaces the sections that are translated into the selected
+// language and keeps the English text of the others, including those whose
+// translation is out of date
+func (l *Lesson) localize(sections []utils.Section) []utils.Section {
+	if i18n.Language() == i18n.DefaultLanguage {
+		return sections
+	}
+	localized := append([]utils.Section(nil), sections...)
+	for i, id := range l.sectionIDs(sections) {
+		if text, ok := i18n.TranslationOf(id, sections[i].Text); ok {
+			localized[i].Text = text
+		}
+	}
+	return localized
+}
+
+// Messages returns the English text of everything a translator can
+// translate in the lessons: category titles, lesson titles and the prose
+// sections of every lesson. Code and output are left out since they rarely
+// need translating, though catalogs may still translate them.
+func Messages() map[string]string {
+	messages := make(map[string]string)
+	for _, c := range Categories {
+		messages["category."+c.ID] = c.Title
+	}
+	for _, l := range registry {
+		if l.sections == nil && l.Run != nil {
+			l.sections = utils.Capture(l.Run)
+		}
+		messages["lesson."+l.ID+".title"] = l.Title
+		ids := l.sectionIDs(l.sections)
+		for i, s := range l.sections {
+			if s.Kind == utils.SectionExplanation || s.Kind == utils.SectionTakeaways {
+				messages[ids[i]] = s.Text
+			}
+		}
+	}
+	return messages
+}
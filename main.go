@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,57 +10,124 @@ import (
 	"strconv"
 	"strings"
 
-	"go-interface-enum-explorer/examples"
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/sandbox"
+	"go-interface-enum-explorer/search"
+	"go-interface-enum-explorer/settings"
 	"go-interface-enum-explorer/utils"
 )
 
 // Version is set during build using ldflags
 var Version = "dev"
 
-// Tutorial topics organized by category and complexity
-var topics = map[string][]string{
-	"interfaces": {
-		"Basic Interfaces",
-		"Interface Implementation",
-		"Empty Interface",
-		"Type Assertion",
-		"Interface Composition",
-	},
-	"enums": {
-		"Basic Enums",
-		"Iota Enums",
-		"String Enums",
-		"Behavior Enums",
-	},
-}
-
-// Example functions mapped to their names
-var exampleFunctions = map[string]func(){
-	"Basic Interfaces":         examples.BasicInterfaces,
-	"Interface Implementation": examples.InterfaceImplementation,
-	"Empty Interface":          examples.EmptyInterface,
-	"Type Assertion":           examples.TypeAssertion,
-	"Interface Composition":    examples.InterfaceComposition,
-	"Basic Enums":              examples.BasicEnums,
-	"Iota Enums":               examples.IotaEnums,
-	"String Enums":             examples.StringEnums,
-	"Behavior Enums":           examples.BehaviorEnums,
+// searchIndex covers every lesson; see lessonIndex
+var searchIndex *search.Index
+
+// lessonIndex returns the search index, building it on the first call.
+// Building it runs every lesson to capture its sections, which takes
+// longer than anything else at startup, and most sessions never search,
+// so it is left until it is needed: the first search of a session is
+// slower than the ones after it.
+func lessonIndex() *search.Index {
+	if searchIndex == nil {
+		searchIndex = search.Build(lessons.All())
+	}
+	return searchIndex
 }
 
+// prefs holds the settings in effect: the settings file with the flags
+// given on the command line applied on top
+var (
+	prefs        = settings.Defaults()
+	settingsPath string
+	noColor      bool
+)
+
 func main() {
+	// When started as the sandbox helper this runs a learner's program
+	// instead of the explorer
+	sandbox.Helper()
+
+	flag.BoolVar(&noColor, "no-color", false, "disable colored output")
+	lineNumbers := flag.Bool("line-numbers", false, "show line numbers in code examples")
+	lessonsDir := flag.String("lessons-dir", "", "load additional Markdown lessons from this directory")
+	accessible := flag.Bool("accessible", false, "screen reader friendly output: sections announced in words, no colors, rules or screen clearing")
+	lang := flag.String("lang", "", "language of the interface and lessons, such as es (defaults to the settings file, then $LANG)")
+	catalog := flag.String("catalog", "", "load translations from a catalog file named after its language, such as de.json")
+	theme := flag.String("theme", "", "color theme: dark, light, high-contrast or monochrome")
+	settingsFile := flag.String("settings", "", "read and save settings in this file instead of the user config directory")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	settingsPath = *settingsFile
+	if settingsPath == "" {
+		if path, err := settings.Path(); err == nil {
+			settingsPath = path
+		}
+	}
+	if settingsPath != "" {
+		loaded, err := settings.Load(settingsPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		} else {
+			prefs = loaded
+		}
+	}
+
+	// Flags given on the command line override the settings file
+	if os.Getenv("EXPLORER_ACCESSIBLE") != "" {
+		prefs.Accessible = true
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "line-numbers":
+			prefs.LineNumbers = *lineNumbers
+		case "accessible":
+			prefs.Accessible = *accessible
+		case "lang":
+			prefs.Language = *lang
+		case "theme":
+			prefs.Theme = *theme
+		}
+	})
+
+	if *catalog != "" {
+		if err := i18n.LoadFile(*catalog); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if err := applySettings(prefs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if *lessonsDir != "" {
+		if err := lessons.LoadDir(*lessonsDir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Args()))
+	}
+
 	clearScreen()
 	displayWelcome()
 
-	scanner := bufio.NewScanner(os.Stdin)
+	scanner := bufio.NewScanner(utils.Stdin())
 
 	for {
 		displayMainMenu()
-		fmt.Print("\nEnter your choice (or 'q' to quit): ")
+		fmt.Print("\n" + i18n.T("prompt.main"))
 		scanner.Scan()
 		choice := strings.TrimSpace(scanner.Text())
 
+		if handleSearch(scanner, choice) {
+			continue
+		}
 		if choice == "q" || choice == "Q" {
-			fmt.Println("Thank you for learning Go interfaces and enums. Happy coding!")
+			fmt.Println(i18n.T("goodbye"))
 			break
 		}
 
@@ -69,71 +137,87 @@ func main() {
 		case "2":
 			browseExamples(scanner)
 		case "3":
+			learningPath(scanner)
+		case "4":
+			browseGlossary(scanner)
+		case "5":
+			compareLessons(scanner)
+		case "6":
+			benchmarkLab(scanner)
+		case "7":
+			editSettings(scanner)
+		case "8":
 			displayHelp()
 			utils.PressEnterToContinue()
 		default:
-			fmt.Println("Invalid choice. Please try again.")
+			fmt.Println(i18n.T("error.invalid_choice"))
 			utils.PressEnterToContinue()
 		}
 	}
 }
 
 func displayWelcome() {
-	utils.PrintColoredTitle("Welcome to Go Interface & Enum Explorer", utils.ColorCyan)
-	fmt.Printf("Version: %s\n\n", Version)
-	fmt.Println("This interactive tool will help you learn about interfaces and enums in Go.")
-	fmt.Println("You'll see examples ranging from basic concepts to advanced usage patterns.")
-	fmt.Println("\nEach example includes:")
-	fmt.Println("- Explanation of the concept")
-	fmt.Println("- Sample code with comments")
-	fmt.Println("- Output of the code execution")
-	fmt.Println("\nLet's begin exploring Go's powerful interface system and enum patterns!")
+	utils.PrintColoredTitle(i18n.T("welcome.title"), utils.ColorCyan)
+	fmt.Println(i18n.T("welcome.version", Version))
+	fmt.Println()
+	fmt.Println(i18n.T("welcome.body"))
 	utils.PressEnterToContinue()
 }
 
 func displayMainMenu() {
 	clearScreen()
-	utils.PrintColoredTitle("Main Menu", utils.ColorGreen)
-	fmt.Println("1. Start Tutorial (guided journey)")
-	fmt.Println("2. Browse Examples (pick specific topics)")
-	fmt.Println("3. Help")
-	fmt.Println("q. Quit")
+	utils.PrintColoredTitle(i18n.T("menu.title"), utils.ColorGreen)
+	fmt.Println("1. " + i18n.T("menu.tutorial"))
+	fmt.Println("2. " + i18n.T("menu.browse"))
+	fmt.Println("3. " + i18n.T("menu.path"))
+	fmt.Println("4. " + i18n.T("menu.glossary"))
+	fmt.Println("5. " + i18n.T("menu.compare"))
+	fmt.Println("6. " + i18n.T("menu.bench"))
+	fmt.Println("7. " + i18n.T("menu.settings"))
+	fmt.Println("8. " + i18n.T("menu.help"))
+	fmt.Println("/text. " + i18n.T("menu.search"))
+	fmt.Println("q. " + i18n.T("menu.quit"))
 }
 
 func tutorialMode(scanner *bufio.Scanner) {
-	var allTopics []string
-
-	// Combine all topics in the right order for a progressive learning experience
-	allTopics = append(allTopics, topics["interfaces"]...)
-	allTopics = append(allTopics, topics["enums"]...)
+	// Lessons come in prerequisite order for a progressive learning experience
+	runTutorial(scanner, i18n.T("tutorial.name"), lessons.All())
+}
 
-	for i, topic := range allTopics {
+// runTutorial guides the learner through a list of lessons one after another
+func runTutorial(scanner *bufio.Scanner, name string, allLessons []*lessons.Lesson) {
+	for i, lesson := range allLessons {
 		clearScreen()
-		utils.PrintColoredTitle(fmt.Sprintf("Tutorial (%d/%d): %s", i+1, len(allTopics), topic), utils.ColorYellow)
-
-		// Run the example for this topic
-		if fn, exists := exampleFunctions[topic]; exists {
-			fn()
-		} else {
-			fmt.Printf("Example for %s is not implemented yet.\n", topic)
-		}
+		lesson.Show(i18n.T("tutorial.heading", name, i+1, len(allLessons), lesson.DisplayTitle()))
 
 		// After showing an example, offer navigation options
-		if i < len(allTopics)-1 {
-			fmt.Println("\nOptions:")
-			fmt.Println("n - Next example")
-			fmt.Println("m - Return to main menu")
-			fmt.Print("\nYour choice: ")
+		if i < len(allLessons)-1 {
+			fmt.Println("\n" + i18n.T("tutorial.options"))
+			fmt.Println(i18n.T("tutorial.next"))
+			fmt.Println(i18n.T("tutorial.escape"))
+			fmt.Println(i18n.T("tutorial.menu"))
+			fmt.Print("\n" + i18n.T("prompt.choice"))
 
 			scanner.Scan()
 			choice := strings.TrimSpace(scanner.Text())
 
+			// Searching or analyzing comes back to this prompt
+			for {
+				if choice == "a" || choice == "A" {
+					showEscapes(lesson)
+				} else if !handleSearch(scanner, choice) {
+					break
+				}
+				fmt.Print("\n" + i18n.T("tutorial.back"))
+				scanner.Scan()
+				choice = strings.TrimSpace(scanner.Text())
+			}
 			if choice == "m" || choice == "M" {
 				break
 			}
 			// Any other input will move to the next example
 		} else {
-			fmt.Println("\nCongratulations! You've completed all the tutorials.")
+			fmt.Println("\n" + i18n.T("tutorial.done"))
 			utils.PressEnterToContinue()
 		}
 	}
@@ -142,100 +226,308 @@ func tutorialMode(scanner *bufio.Scanner) {
 func browseExamples(scanner *bufio.Scanner) {
 	for {
 		clearScreen()
-		utils.PrintColoredTitle("Browse Examples", utils.ColorBlue)
+		utils.PrintColoredTitle(i18n.T("browse.title"), utils.ColorBlue)
 
-		fmt.Println("Categories:")
-		fmt.Println("1. Interfaces")
-		fmt.Println("2. Enums")
-		fmt.Println("b. Back to Main Menu")
+		fmt.Println(i18n.T("browse.categories"))
+		for i, category := range lessons.Categories {
+			fmt.Printf("%d. %s\n", i+1, lessons.CategoryTitle(category.ID))
+		}
+		fmt.Println("b. " + i18n.T("menu.back"))
 
-		fmt.Print("\nSelect a category (or 'b' to go back): ")
+		fmt.Print("\n" + i18n.T("prompt.category"))
 		scanner.Scan()
-		category := strings.TrimSpace(scanner.Text())
+		choice := strings.TrimSpace(scanner.Text())
 
-		if category == "b" || category == "B" {
+		if handleSearch(scanner, choice) {
+			continue
+		}
+		if choice == "b" || choice == "B" {
 			break
 		}
 
-		var topicList []string
-		var categoryTitle string
-
-		switch category {
-		case "1":
-			topicList = topics["interfaces"]
-			categoryTitle = "Interfaces"
-		case "2":
-			topicList = topics["enums"]
-			categoryTitle = "Enums"
-		default:
-			fmt.Println("Invalid category. Please try again.")
+		categoryIndex, err := strconv.Atoi(choice)
+		if err != nil || categoryIndex < 1 || categoryIndex > len(lessons.Categories) {
+			fmt.Println(i18n.T("error.invalid_category"))
 			utils.PressEnterToContinue()
 			continue
 		}
 
+		category := lessons.Categories[categoryIndex-1]
+		lessonList := lessons.InCategory(category.ID)
+
 		for {
 			clearScreen()
-			utils.PrintColoredTitle(fmt.Sprintf("%s Examples", categoryTitle), utils.ColorBlue)
-
-			for i, topic := range topicList {
-				fmt.Printf("%d. %s\n", i+1, topic)
+			utils.PrintColoredTitle(i18n.T("browse.examples", lessons.CategoryTitle(category.ID)), utils.ColorBlue)
+
+			for i, lesson := range lessonList {
+				if lesson.Difficulty != "" {
+					fmt.Printf("%d. %s (%s)\n", i+1, lesson.DisplayTitle(), i18n.T("difficulty."+lesson.Difficulty))
+				} else {
+					fmt.Printf("%d. %s\n", i+1, lesson.DisplayTitle())
+				}
 			}
-			fmt.Println("b. Back to Categories")
+			fmt.Println("b. " + i18n.T("browse.back"))
 
-			fmt.Print("\nSelect an example (or 'b' to go back): ")
+			fmt.Print("\n" + i18n.T("prompt.example"))
 			scanner.Scan()
 			topicChoice := strings.TrimSpace(scanner.Text())
 
+			if handleSearch(scanner, topicChoice) {
+				continue
+			}
 			if topicChoice == "b" || topicChoice == "B" {
 				break
 			}
 
 			topicIndex, err := strconv.Atoi(topicChoice)
-			if err != nil || topicIndex < 1 || topicIndex > len(topicList) {
-				fmt.Println("Invalid selection. Please try again.")
+			if err != nil || topicIndex < 1 || topicIndex > len(lessonList) {
+				fmt.Println(i18n.T("error.invalid_selection"))
 				utils.PressEnterToContinue()
 				continue
 			}
 
-			selectedTopic := topicList[topicIndex-1]
+			selected := lessonList[topicIndex-1]
 			clearScreen()
-			utils.PrintColoredTitle(selectedTopic, utils.ColorYellow)
+			selected.Show(selected.DisplayTitle())
+
+			utils.PressEnterToContinue()
+		}
+	}
+}
+
+func learningPath(scanner *bufio.Scanner) {
+	for {
+		clearScreen()
+		utils.PrintColoredTitle(i18n.T("path.title"), utils.ColorCyan)
+
+		allLessons := lessons.All()
+		fmt.Println(i18n.T("path.question"))
+		for i, lesson := range allLessons {
+			fmt.Printf("%d. %s\n", i+1, lesson.DisplayTitle())
+		}
+		fmt.Println("b. " + i18n.T("menu.back"))
+
+		fmt.Print("\n" + i18n.T("prompt.goal"))
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
 
-			if fn, exists := exampleFunctions[selectedTopic]; exists {
-				fn()
-			} else {
-				fmt.Printf("Example for %s is not implemented yet.\n", selectedTopic)
+		if handleSearch(scanner, choice) {
+			continue
+		}
+		if choice == "b" || choice == "B" {
+			break
+		}
+
+		index, err := strconv.Atoi(choice)
+		if err != nil || index < 1 || index > len(allLessons) {
+			fmt.Println(i18n.T("error.invalid_selection"))
+			utils.PressEnterToContinue()
+			continue
+		}
+
+		goal := allLessons[index-1]
+		path, err := lessons.PathTo(goal.ID)
+		if err != nil {
+			fmt.Println(err)
+			utils.PressEnterToContinue()
+			continue
+		}
+
+		clearScreen()
+		heading := i18n.T("path.heading", goal.DisplayTitle())
+		utils.PrintColoredTitle(heading, utils.ColorCyan)
+		printPath(path)
+
+		fmt.Print("\n" + i18n.T("prompt.start_path"))
+		scanner.Scan()
+		if isYes(scanner.Text()) {
+			runTutorial(scanner, heading, path)
+		}
+	}
+}
+
+// printPath lists the lessons of a learning path with what each one needs
+func printPath(path []*lessons.Lesson) {
+	fmt.Println(i18n.T("path.count", len(path)))
+	fmt.Println()
+	for i, lesson := range path {
+		fmt.Printf("%d. %s\n", i+1, lesson.DisplayTitle())
+		if len(lesson.Prerequisites) > 0 {
+			var names []string
+			for _, id := range lesson.Prerequisites {
+				if p := lessons.Lookup(id); p != nil {
+					names = append(names, p.DisplayTitle())
+				}
 			}
+			fmt.Println("   " + i18n.T("path.builds_on", strings.Join(names, ", ")))
+		}
+	}
+}
+
+func browseGlossary(scanner *bufio.Scanner) {
+	for {
+		clearScreen()
+		utils.PrintColoredTitle(i18n.T("glossary.title"), utils.ColorMagenta)
 
+		for i, term := range lessons.Glossary {
+			fmt.Printf("%d. %s\n", i+1, term.Name)
+		}
+		fmt.Println("b. " + i18n.T("menu.back"))
+
+		fmt.Print("\n" + i18n.T("prompt.term"))
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
+
+		if handleSearch(scanner, choice) {
+			continue
+		}
+		if choice == "b" || choice == "B" {
+			break
+		}
+
+		term := lessons.LookupTerm(choice)
+		if index, err := strconv.Atoi(choice); err == nil && index >= 1 && index <= len(lessons.Glossary) {
+			term = lessons.Glossary[index-1]
+		}
+		if term == nil {
+			fmt.Println(i18n.T("error.unknown_term"))
 			utils.PressEnterToContinue()
+			continue
 		}
+
+		clearScreen()
+		using := printTerm(term)
+		if len(using) == 0 {
+			utils.PressEnterToContinue()
+			continue
+		}
+
+		fmt.Print("\n" + i18n.T("prompt.open_lesson"))
+		scanner.Scan()
+		index, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+		if err != nil || index < 1 || index > len(using) {
+			continue
+		}
+		selected := using[index-1]
+		clearScreen()
+		selected.Show(selected.DisplayTitle())
+		utils.PressEnterToContinue()
 	}
 }
 
-func displayHelp() {
-	clearScreen()
-	utils.PrintColoredTitle("Help", utils.ColorMagenta)
+// printTerm shows a glossary term with its definition, example and the
+// numbered lessons that use it, and returns those lessons
+func printTerm(term *lessons.Term) []*lessons.Lesson {
+	utils.PrintColoredTitle(term.Name, utils.ColorMagenta)
+	fmt.Println(term.Definition)
+	if term.Example != "" {
+		fmt.Println()
+		for _, line := range utils.HighlightCode(term.Example, false) {
+			fmt.Println("    " + line)
+		}
+	}
 
-	fmt.Println("How to use this tool:")
-	fmt.Println("1. Tutorial Mode: Guides you through all examples in a logical order.")
-	fmt.Println("2. Browse Examples: Pick specific topics you're interested in.")
+	using := term.Lessons()
+	if len(using) > 0 {
+		fmt.Println("\n" + i18n.T("glossary.used_in"))
+		for i, l := range using {
+			fmt.Printf("%d. %s\n", i+1, l.DisplayTitle())
+		}
+	}
+	return using
+}
+
+// handleSearch runs a search when menu input starts with "/" and reports
+// whether it did, so every menu accepts /text alongside its own choices
+func handleSearch(scanner *bufio.Scanner, input string) bool {
+	if !strings.HasPrefix(input, "/") {
+		return false
+	}
+	searchLessons(scanner, strings.TrimSpace(strings.TrimPrefix(input, "/")))
+	return true
+}
+
+// searchLessons shows the lessons matching a query and opens the one the
+// learner picks
+func searchLessons(scanner *bufio.Scanner, query string) {
+	for {
+		if query == "" {
+			fmt.Print("\n" + i18n.T("prompt.search"))
+			scanner.Scan()
+			if query = strings.TrimSpace(scanner.Text()); query == "" {
+				return
+			}
+		}
+
+		clearScreen()
+		utils.PrintColoredTitle(i18n.T("search.heading", query), utils.ColorCyan)
+		results := lessonIndex().Search(query, 10)
+		if len(results) == 0 {
+			fmt.Println(i18n.T("search.none"))
+			utils.PressEnterToContinue()
+			return
+		}
+		printSearchResults(results)
+
+		fmt.Print("\n" + i18n.T("prompt.search_results"))
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
 
-	fmt.Println("\nAbout Go Interfaces:")
-	fmt.Println("- Interfaces in Go define behavior, not structure.")
-	fmt.Println("- Types implement interfaces implicitly (no 'implements' keyword).")
-	fmt.Println("- Interfaces can be composed of other interfaces.")
-	fmt.Println("- The empty interface (interface{}) can hold values of any type.")
+		if strings.HasPrefix(choice, "/") {
+			query = strings.TrimSpace(strings.TrimPrefix(choice, "/"))
+			continue
+		}
+		index, err := strconv.Atoi(choice)
+		if err != nil || index < 1 || index > len(results) {
+			return
+		}
+		selected := results[index-1].Lesson
+		clearScreen()
+		selected.Show(selected.DisplayTitle())
+		utils.PressEnterToContinue()
+	}
+}
 
-	fmt.Println("\nAbout Go Enums:")
-	fmt.Println("- Go doesn't have built-in enums, but provides patterns to implement them.")
-	fmt.Println("- The iota identifier is used to create incrementing constants.")
-	fmt.Println("- Type safety can be achieved with custom types and constants.")
-	fmt.Println("- String representations can be added with methods.")
+// printSearchResults lists ranked results with the matching lines of each
+// lesson, the matched words highlighted
+func printSearchResults(results []search.Result) {
+	mark := func(word string) string {
+		return utils.Colorize(utils.ColorYellow, word)
+	}
+	for i, r := range results {
+		fmt.Printf("%d. %s (%s)\n", i+1, r.Lesson.DisplayTitle(), lessons.CategoryTitle(r.Lesson.Category))
+		for _, s := range r.Snippets {
+			label := i18n.T("search.field." + s.Field.Name())
+			fmt.Printf("   %s %s\n", utils.Colorize(utils.ColorGray, label+":"), s.Highlight(mark))
+		}
+	}
+}
+
+func displayHelp() {
+	clearScreen()
+	utils.PrintColoredTitle(i18n.T("help.title"), utils.ColorMagenta)
+
+	fmt.Println(i18n.T("help.usage"))
+	fmt.Println("\n" + i18n.T("help.pager"))
+	fmt.Println("\n" + i18n.T("help.interfaces"))
+	fmt.Println("\n" + i18n.T("help.enums"))
+	fmt.Println("\n" + i18n.T("help.tip"))
+}
 
-	fmt.Println("\nTip: Running the examples and reviewing the code is the best way to learn!")
+// isYes reports whether an answer means yes, in English or in the selected
+// language
+func isYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == strings.ToLower(i18n.T("answer.yes"))
 }
 
+// clearScreen wipes the terminal, except in accessible mode, where the
+// screen reader user keeps the earlier output to review
 func clearScreen() {
+	if utils.Accessible() {
+		fmt.Println()
+		return
+	}
 	var cmd *exec.Cmd
 	if runtime.GOOS == "windows" {
 		cmd = exec.Command("cmd", "/c", "cls")
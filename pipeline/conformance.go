@@ -0,0 +1,212 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Subject is a writer for Conformance to check. New is called for every
+// check, so each one starts from a fresh writer.
+type Subject struct {
+	Name string
+
+	// New returns a new writer and a function that returns everything that
+	// has reached its destination so far
+	New func() (w Writer, output func() (string, error), err error)
+
+	// Want returns the output expected once input has been written and
+	// flushed. Nil means the input itself.
+	Want func(input string) string
+}
+
+// conformanceInput is written by the checks, one string per write. It has
+// lines written whole, in pieces and several at once, text that is not
+// ASCII, a secret to redact and an unfinished last line.
+var conformanceInput = []string{
+	"Hello, World!\n",
+	"a line written ",
+	"in ",
+	"pieces\n",
+	"two lines\nat once\n",
+	"ünïcödé ✓\n",
+	"login password=hunter2 ok\n",
+	"no newline at the end",
+}
+
+// Conformance checks that the writer of s keeps the contract of the
+// package, and returns one error per broken rule. Writers that are also an
+// io.Closer are closed after each check.
+func Conformance(s Subject) []error {
+	want := s.Want
+	if want == nil {
+		want = func(input string) string { return input }
+	}
+	all := strings.Join(conformanceInput, "")
+
+	var errs []error
+	fail := func(check, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s: %s", s.Name, check, fmt.Sprintf(format, args...)))
+	}
+	// run gives a check a fresh writer, then flushes and reads it
+	run := func(check string, write func(w Writer) bool, input string) {
+		w, output, err := s.New()
+		if err != nil {
+			fail(check, "cannot create the writer: %v", err)
+			return
+		}
+		if c, ok := w.(io.Closer); ok {
+			defer c.Close()
+		}
+		if !write(w) {
+			return
+		}
+		if err := Flush(w); err != nil {
+			fail(check, "Flush failed: %v", err)
+			return
+		}
+		got, err := output()
+		if err != nil {
+			fail(check, "cannot read the output: %v", err)
+			return
+		}
+		if expected := want(input); got != expected {
+			fail(check, "the output is %q, want %q", got, expected)
+		}
+	}
+
+	run("empty write", func(w Writer) bool {
+		if n, err := w.Write(""); n != 0 || err != nil {
+			fail("empty write", "Write(\"\") = %d, %v, want 0, nil", n, err)
+			return false
+		}
+		return true
+	}, "")
+
+	run("writes", func(w Writer) bool {
+		for _, data := range conformanceInput {
+			if n, err := w.Write(data); n != len(data) || err != nil {
+				fail("writes", "Write(%q) = %d, %v, want %d, nil", data, n, err, len(data))
+				return false
+			}
+		}
+		return true
+	}, all)
+
+	run("io.Writer", func(w Writer) bool {
+		iw := ToIO(w)
+		for _, data := range conformanceInput {
+			if n, err := iw.Write([]byte(data)); n != len(data) || err != nil {
+				fail("io.Writer", "Write(%q) = %d, %v, want %d, nil", data, n, err, len(data))
+				return false
+			}
+		}
+		return true
+	}, all)
+
+	run("fmt.Fprintf", func(w Writer) bool {
+		if _, err := fmt.Fprintf(ToIO(w), "%s", all); err != nil {
+			fail("fmt.Fprintf", "%v", err)
+			return false
+		}
+		return true
+	}, all)
+
+	run("round trip", func(w Writer) bool {
+		if n, err := FromIO(ToIO(w)).Write(all); n != len(all) || err != nil {
+			fail("round trip", "FromIO(ToIO(w)).Write = %d, %v, want %d, nil", n, err, len(all))
+			return false
+		}
+		return true
+	}, all)
+
+	return errs
+}
+
+// conformanceClock is the fixed time of the Timestamp subject
+var conformanceClock = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// conformanceSecret is the pattern of the Redact subject
+var conformanceSecret = regexp.MustCompile(`password=(\S+)`)
+
+// prefixLines is an independent version of Prefix, for the checks
+func prefixLines(prefix, s string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line != "" {
+			b.WriteString(prefix + line)
+		}
+	}
+	return b.String()
+}
+
+// buffered returns a subject made of a Buffer behind the middleware
+func buffered(name string, want func(string) string, middleware ...Middleware) Subject {
+	return Subject{
+		Name: name,
+		New: func() (Writer, func() (string, error), error) {
+			var b Buffer
+			return Chain(&b, middleware...), func() (string, error) { return b.String(), nil }, nil
+		},
+		Want: want,
+	}
+}
+
+// Subjects returns every writer of the package, ready for Conformance.
+// Files are created in dir.
+func Subjects(dir string) []Subject {
+	count := 0
+	return []Subject{
+		buffered("Buffer", nil),
+		{
+			Name: "RotatingFile",
+			New: func() (Writer, func() (string, error), error) {
+				count++
+				// A small limit makes the checks rotate several times; with
+				// enough backups nothing is lost
+				r, err := OpenRotating(filepath.Join(dir, fmt.Sprintf("rotating-%d.log", count)), 24, 100)
+				if err != nil {
+					return nil, nil, err
+				}
+				return r, func() (string, error) {
+					var b strings.Builder
+					for _, name := range r.Files() {
+						data, err := os.ReadFile(name)
+						if err != nil {
+							return "", err
+						}
+						b.Write(data)
+					}
+					return b.String(), nil
+				}, nil
+			},
+		},
+		{
+			Name: "FromIO",
+			New: func() (Writer, func() (string, error), error) {
+				var b strings.Builder
+				return FromIO(&b), func() (string, error) { return b.String(), nil }, nil
+			},
+		},
+		buffered("Prefix", func(s string) string { return prefixLines("app: ", s) }, Prefix("app: ")),
+		buffered("Timestamp", func(s string) string { return prefixLines("12:00:00 ", s) }, Timestamp("15:04:05", func() time.Time { return conformanceClock })),
+		buffered("Redact", func(s string) string { return strings.ReplaceAll(s, "hunter2", Redacted) }, Redact(conformanceSecret)),
+		buffered("LineBuffering", nil, LineBuffering()),
+		buffered("Transform", strings.ToUpper, Transform(strings.ToUpper)),
+		{
+			Name: "Tee",
+			New: func() (Writer, func() (string, error), error) {
+				var a, b Buffer
+				return Tee(&a, &b), func() (string, error) { return a.String() + b.String(), nil }, nil
+			},
+			Want: func(s string) string { return s + s },
+		},
+		buffered("Chain", func(s string) string {
+			return prefixLines("app: 12:00:00 ", strings.ReplaceAll(s, "hunter2", Redacted))
+		}, LineBuffering(), Redact(conformanceSecret), Timestamp("15:04:05", func() time.Time { return conformanceClock }), Prefix("app: ")),
+	}
+}
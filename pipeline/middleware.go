@@ -0,0 +1,231 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// wrapper is embedded in middleware to pass Flush on to the next writer
+type wrapper struct {
+	next Writer
+}
+
+func (w wrapper) Flush() error {
+	return Flush(w.next)
+}
+
+// linePrefixer puts a prefix at the start of every line. It remembers
+// whether the last write ended a line, so lines written in pieces get the
+// prefix once.
+type linePrefixer struct {
+	wrapper
+	prefix func() string
+
+	mu      sync.Mutex
+	midLine bool
+}
+
+func (p *linePrefixer) Write(data string) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if data == "" {
+		return 0, nil
+	}
+	var b strings.Builder
+	for rest := data; rest != ""; {
+		if !p.midLine {
+			b.WriteString(p.prefix())
+		}
+		i := strings.IndexByte(rest, '\n')
+		if i < 0 {
+			b.WriteString(rest)
+			p.midLine = true
+			break
+		}
+		b.WriteString(rest[:i+1])
+		rest = rest[i+1:]
+		p.midLine = false
+	}
+	if _, err := p.next.Write(b.String()); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Prefix puts prefix at the start of every line
+func Prefix(prefix string) Middleware {
+	return func(next Writer) Writer {
+		return &linePrefixer{wrapper: wrapper{next}, prefix: func() string { return prefix }}
+	}
+}
+
+// Timestamp puts the time at the start of every line, formatted with
+// layout and followed by a space. The time comes from clock, which is
+// time.Now when nil.
+func Timestamp(layout string, clock func() time.Time) Middleware {
+	if clock == nil {
+		clock = time.Now
+	}
+	return func(next Writer) Writer {
+		return &linePrefixer{wrapper: wrapper{next}, prefix: func() string { return clock().Format(layout) + " " }}
+	}
+}
+
+// Redacted replaces what Redact removes
+const Redacted = "[REDACTED]"
+
+// Redact replaces every match of the patterns with [REDACTED]. A pattern
+// with a group redacts only the group and keeps the rest of the match, so
+// `password=(\S+)` leaves "password=" in place. Matches are found within
+// one write: put LineBuffering before Redact so that a secret written in
+// pieces is still found.
+func Redact(patterns ...*regexp.Regexp) Middleware {
+	return func(next Writer) Writer {
+		return &redactor{wrapper: wrapper{next}, patterns: patterns}
+	}
+}
+
+type redactor struct {
+	wrapper
+	patterns []*regexp.Regexp
+}
+
+func (r *redactor) Write(data string) (int, error) {
+	if data == "" {
+		return 0, nil
+	}
+	out := data
+	for _, p := range r.patterns {
+		out = redact(p, out)
+	}
+	if _, err := r.next.Write(out); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// redact replaces the matches of p in s, or the first group of each match
+// when p has groups
+func redact(p *regexp.Regexp, s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range p.FindAllStringSubmatchIndex(s, -1) {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(Redacted)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// LineBuffering holds data back until a line is complete and passes on
+// whole lines only. Flush passes on an unfinished last line.
+func LineBuffering() Middleware {
+	return func(next Writer) Writer {
+		return &lineBuffer{wrapper: wrapper{next}}
+	}
+}
+
+type lineBuffer struct {
+	wrapper
+
+	mu      sync.Mutex
+	pending strings.Builder
+}
+
+func (l *lineBuffer) Write(data string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if data == "" {
+		return 0, nil
+	}
+	i := strings.LastIndexByte(data, '\n')
+	if i < 0 {
+		l.pending.WriteString(data)
+		return len(data), nil
+	}
+	lines := l.pending.String() + data[:i+1]
+	l.pending.Reset()
+	l.pending.WriteString(data[i+1:])
+	if _, err := l.next.Write(lines); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (l *lineBuffer) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.pending.Len() > 0 {
+		rest := l.pending.String()
+		l.pending.Reset()
+		if _, err := l.next.Write(rest); err != nil {
+			return err
+		}
+	}
+	return Flush(l.next)
+}
+
+// Transform changes data with f before passing it on, as the lesson's
+// ImplUppercaseWriter does with strings.ToUpper
+func Transform(f func(string) string) Middleware {
+	return func(next Writer) Writer {
+		return &transformer{wrapper: wrapper{next}, f: f}
+	}
+}
+
+type transformer struct {
+	wrapper
+	f func(string) string
+}
+
+func (t *transformer) Write(data string) (int, error) {
+	if data == "" {
+		return 0, nil
+	}
+	if _, err := t.next.Write(t.f(data)); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Tee writes everything to each of the writers. A write goes to all of
+// them even when one fails; the first error is returned.
+func Tee(writers ...Writer) Writer {
+	return tee(writers)
+}
+
+type tee []Writer
+
+func (t tee) Write(data string) (int, error) {
+	if data == "" {
+		return 0, nil
+	}
+	var first error
+	for i, w := range t {
+		if _, err := w.Write(data); err != nil && first == nil {
+			first = fmt.Errorf("tee writer %d: %w", i, err)
+		}
+	}
+	if first != nil {
+		return 0, first
+	}
+	return len(data), nil
+}
+
+func (t tee) Flush() error {
+	var first error
+	for _, w := range t {
+		if err := Flush(w); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
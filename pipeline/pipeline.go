@@ -0,0 +1,122 @@
+// Package pipeline builds logging pipelines out of small writers. A Writer
+// has the single method of the Interface Implementation lesson's
+// ImplWriter, Write(data string) (int, error), so the lesson's writers and
+// the ones here can be plugged into each other without conversion.
+//
+// The package provides sinks, which send data somewhere (a Buffer, a
+// RotatingFile or any io.Writer), and middleware, which changes data on
+// its way to the next writer (Prefix, Timestamp, Redact, LineBuffering,
+// Transform). Tee copies data to several writers. Every writer keeps the
+// contract that Conformance checks:
+//
+//   - Write returns len(data) and a nil error when it succeeds
+//   - writing "" does nothing
+//   - after Flush, everything written has reached the sink, in order
+package pipeline
+
+import "io"
+
+// Writer is anything that accepts text. It has the same method as the
+// ImplWriter interface, so any ImplWriter is a Writer and the other way
+// around.
+type Writer interface {
+	Write(data string) (int, error)
+}
+
+// WriterFunc turns a function into a Writer
+type WriterFunc func(data string) (int, error)
+
+func (f WriterFunc) Write(data string) (int, error) {
+	return f(data)
+}
+
+// Flusher is a writer that holds data back until it is flushed, such as
+// a line buffer. Middleware passes Flush on to the writer it wraps.
+type Flusher interface {
+	Flush() error
+}
+
+// Flush flushes w if it is a Flusher
+func Flush(w Writer) error {
+	if f, ok := w.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Middleware wraps a writer in another one
+type Middleware func(next Writer) Writer
+
+// Chain wraps sink in the middleware. Data goes through the middleware in
+// the order given, and the last one passes it to sink. The last one also
+// changes the data last, so Chain(sink, Timestamp(...), Prefix("app: "))
+// writes lines such as "app: 12:00:00 started".
+func Chain(sink Writer, middleware ...Middleware) Writer {
+	w := sink
+	for i := len(middleware) - 1; i >= 0; i-- {
+		w = middleware[i](w)
+	}
+	return w
+}
+
+// ToIO returns w as an io.Writer, so it can be used with fmt.Fprintf,
+// log.New, io.Copy and the rest of the standard library. Writer values
+// made by FromIO are unwrapped instead.
+func ToIO(w Writer) io.Writer {
+	if f, ok := w.(fromIO); ok {
+		return f.w
+	}
+	return toIO{w}
+}
+
+// FromIO returns an io.Writer, such as os.Stdout or a *bufio.Writer, as a
+// Writer. io.Writer values made by ToIO are unwrapped instead.
+func FromIO(w io.Writer) Writer {
+	if t, ok := w.(toIO); ok {
+		return t.w
+	}
+	return fromIO{w}
+}
+
+// toIO is a Writer seen as an io.Writer
+type toIO struct {
+	w Writer
+}
+
+func (t toIO) Write(p []byte) (int, error) {
+	if _, err := t.w.Write(string(p)); err != nil {
+		// The writer may have changed the data, so its count says nothing
+		// about p
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteString avoids copying strings that io.WriteString passes in
+func (t toIO) WriteString(s string) (int, error) {
+	if _, err := t.w.Write(s); err != nil {
+		return 0, err
+	}
+	return len(s), nil
+}
+
+func (t toIO) Flush() error {
+	return Flush(t.w)
+}
+
+// fromIO is an io.Writer seen as a Writer
+type fromIO struct {
+	w io.Writer
+}
+
+func (f fromIO) Write(data string) (int, error) {
+	return io.WriteString(f.w, data)
+}
+
+// Flush flushes io.Writers that buffer, such as *bufio.Writer
+func (f fromIO) Flush() error {
+	if fl, ok := f.w.(Flusher); ok {
+		return fl.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	for _, s := range Subjects(t.TempDir()) {
+		s := s
+		t.Run(s.Name, func(t *testing.T) {
+			for _, err := range Conformance(s) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	r, err := OpenRotating(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each write fills most of a file, so every one after the first rotates
+	for _, data := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if n, err := r.Write(data); n != len(data) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v, want %d, nil", data, n, err, len(data))
+		}
+	}
+	// A write larger than the limit is not split, but gets a file of its own
+	if _, err := r.Write("a line longer than the limit\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{path + ".2", path + ".1", path}
+	if files := r.Files(); !reflect.DeepEqual(files, want) {
+		t.Fatalf("Files() = %v, want %v", files, want)
+	}
+	// first and second were rotated past the two backups and removed
+	for i, content := range []string{"third\n", "fourth\n", "a line longer than the limit\n"} {
+		if got := readFile(t, want[i]); got != content {
+			t.Errorf("%s holds %q, want %q", filepath.Base(want[i]), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a third backup exists: %v", err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write("late\n"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: err = %v, want ErrClosed", err)
+	}
+	if err := r.Flush(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush after Close: err = %v, want ErrClosed", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Without backups a full file is started over
+	r, err := OpenRotating(path, 8, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.Write("new\n")
+	if got := readFile(t, path); got != "old\nnew\n" {
+		t.Fatalf("after reopening the file holds %q, want the old line kept", got)
+	}
+	r.Write("newer\n")
+	if got := readFile(t, path); got != "newer\n" {
+		t.Errorf("after rotating without backups the file holds %q, want %q", got, "newer\n")
+	}
+	if files := r.Files(); !reflect.DeepEqual(files, []string{path}) {
+		t.Errorf("Files() = %v, want only the current file", files)
+	}
+}
+
+func TestRotatingFileRotateError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	r, err := OpenRotating(path, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	// A directory where the backup should go makes the rename fail
+	if err := os.Mkdir(path+".1", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Write("first\n")
+	_, rotateErr := r.Write("second\n")
+	var linkErr *os.LinkError
+	if !errors.As(rotateErr, &linkErr) {
+		t.Fatalf("the write that rotates: err = %v, want the rename's *os.LinkError", rotateErr)
+	}
+
+	// The file is gone, and later writes say why
+	for name, err := range map[string]error{
+		"Write": func() error { _, err := r.Write("third\n"); return err }(),
+		"Flush": r.Flush(),
+	} {
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("%s after the failed rotation: err = %v, want ErrClosed", name, err)
+		}
+		if !errors.Is(err, rotateErr) || !strings.Contains(err.Error(), rotateErr.Error()) {
+			t.Errorf("%s after the failed rotation: err = %v, want it to wrap %v", name, err, rotateErr)
+		}
+	}
+	if got := readFile(t, path); got != "first\n" {
+		t.Errorf("the file holds %q, want only the write before the rotation", got)
+	}
+}
+
+func TestOpenRotatingLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if _, err := OpenRotating(path, 0, 1); err == nil {
+		t.Error("OpenRotating with a zero size limit does not fail")
+	}
+	if _, err := OpenRotating(path, 10, -1); err == nil {
+		t.Error("OpenRotating with negative backups does not fail")
+	}
+}
+
+func TestRedactAcrossWrites(t *testing.T) {
+	secret := regexp.MustCompile(`password=(\S+)`)
+	pieces := []string{"login pass", "word=hun", "ter2 ok\n"}
+
+	// Redact on its own sees one write at a time and misses the secret
+	var leaky Buffer
+	w := Chain(&leaky, Redact(secret))
+	for _, data := range pieces {
+		w.Write(data)
+	}
+	if !strings.Contains(leaky.String(), "hunter2") {
+		t.Fatalf("Redact found a secret written in pieces: %q; the documentation says it cannot", leaky.String())
+	}
+
+	// With LineBuffering before it the whole line is redacted
+	var safe Buffer
+	w = Chain(&safe, LineBuffering(), Redact(secret))
+	for _, data := range pieces {
+		w.Write(data)
+	}
+	if err := Flush(w); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := safe.String(), "login password=[REDACTED] ok\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRedactWithoutGroup(t *testing.T) {
+	var b Buffer
+	w := Chain(&b, Redact(regexp.MustCompile(`\d{4}-\d{4}`), regexp.MustCompile(`token=(\w+)`)))
+	w.Write("card 1234-5678, token=abc and token=def\n")
+	if got, want := b.String(), "card [REDACTED], token=[REDACTED] and token=[REDACTED]\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// failing is a sink whose writes and flushes fail
+type failing struct {
+	writeErr, flushErr error
+	writes             []string
+}
+
+func (f *failing) Write(data string) (int, error) {
+	f.writes = append(f.writes, data)
+	if f.writeErr != nil {
+		return 0, f.writeErr
+	}
+	return len(data), nil
+}
+
+func (f *failing) Flush() error {
+	return f.flushErr
+}
+
+func TestLineBufferingErrors(t *testing.T) {
+	errWrite := errors.New("disk full")
+	sink := &failing{writeErr: errWrite}
+	w := Chain(sink, LineBuffering())
+
+	// Nothing reaches the sink until a line is complete
+	if n, err := w.Write("half a "); n != 7 || err != nil {
+		t.Errorf("Write of an unfinished line = %d, %v, want 7, nil", n, err)
+	}
+	if n, err := w.Write("line\nand more"); n != 0 || !errors.Is(err, errWrite) {
+		t.Errorf("Write that completes a line = %d, %v, want 0 and the sink's error", n, err)
+	}
+	if want := []string{"half a line\n"}; !reflect.DeepEqual(sink.writes, want) {
+		t.Errorf("the sink got %q, want %q", sink.writes, want)
+	}
+	// Flush passes on the unfinished last line and reports its error
+	if err := Flush(w); !errors.Is(err, errWrite) {
+		t.Errorf("Flush = %v, want the sink's error", err)
+	}
+	if last := sink.writes[len(sink.writes)-1]; last != "and more" {
+		t.Errorf("Flush wrote %q, want the unfinished line", last)
+	}
+
+	// Once the line is written, the error of the sink's own Flush comes back
+	errFlush := errors.New("sync failed")
+	sink = &failing{flushErr: errFlush}
+	w = Chain(sink, LineBuffering(), Prefix("> "))
+	w.Write("no newline")
+	if err := Flush(w); !errors.Is(err, errFlush) {
+		t.Errorf("Flush = %v, want the sink's Flush error", err)
+	}
+	if want := []string{"> no newline"}; !reflect.DeepEqual(sink.writes, want) {
+		t.Errorf("the sink got %q, want %q", sink.writes, want)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	errWrite := errors.New("broken pipe")
+	broken := WriterFunc(func(string) (int, error) { return 0, errWrite })
+	for name, w := range map[string]Writer{
+		"Prefix":    Chain(broken, Prefix("> ")),
+		"Redact":    Chain(broken, Redact(regexp.MustCompile(`x`))),
+		"Transform": Chain(broken, Transform(strings.ToUpper)),
+	} {
+		if n, err := w.Write("text\n"); n != 0 || !errors.Is(err, errWrite) {
+			t.Errorf("%s: Write = %d, %v, want 0 and the error", name, n, err)
+		}
+		if n, err := ToIO(w).Write([]byte("text\n")); n != 0 || !errors.Is(err, errWrite) {
+			t.Errorf("%s: ToIO(w).Write = %d, %v, want 0 and the error", name, n, err)
+		}
+		if _, err := fmt.Fprintf(ToIO(w), "text\n"); !errors.Is(err, errWrite) {
+			t.Errorf("%s: fmt.Fprintf = %v, want the error", name, err)
+		}
+	}
+}
+
+func TestTeeWritesToAll(t *testing.T) {
+	errWrite := errors.New("broken pipe")
+	var before, after Buffer
+	w := Tee(&before, WriterFunc(func(string) (int, error) { return 0, errWrite }), &after)
+	if n, err := w.Write("text\n"); n != 0 || !errors.Is(err, errWrite) {
+		t.Errorf("Write = %d, %v, want 0 and the error", n, err)
+	}
+	if before.String() != "text\n" || after.String() != "text\n" {
+		t.Errorf("the other writers got %q and %q, want the text in both", before.String(), after.String())
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,190 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Buffer keeps everything written to it in memory. The zero value is an
+// empty buffer ready to use, and it is safe for concurrent use.
+type Buffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *Buffer) Write(data string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.WriteString(data)
+}
+
+// String returns everything written so far
+func (b *Buffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+// Reset empties the buffer
+func (b *Buffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.b.Reset()
+}
+
+// ErrClosed is returned by a RotatingFile written to after Close, or after
+// a failed rotation left it without a file
+var ErrClosed = errors.New("pipeline: write to a closed file")
+
+// rotateError is returned by the writes that follow a failed rotation. It
+// is ErrClosed for errors.Is, and unwraps to the reason the rotation failed.
+type rotateError struct {
+	err error
+}
+
+func (e *rotateError) Error() string {
+	return "pipeline: write to a file that could not be rotated: " + e.err.Error()
+}
+
+func (e *rotateError) Unwrap() error        { return e.err }
+func (e *rotateError) Is(target error) bool { return target == ErrClosed }
+
+// RotatingFile writes to a file and starts a new one when the file would
+// grow past a size limit. The full file is renamed to path.1, an older
+// path.1 to path.2 and so on; files beyond the number of backups are
+// removed. A single write is never split, so a write larger than the limit
+// gets a file of its own. It is safe for concurrent use.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	backups  int
+	f        *os.File
+	size     int64
+	err      error // why the last rotation failed, if it left no file
+}
+
+// OpenRotating opens path for appending, creating it if needed, and
+// rotates it whenever it would grow past maxBytes, keeping up to backups
+// old files
+func OpenRotating(path string, maxBytes int64, backups int) (*RotatingFile, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("pipeline: rotating %s: the size limit must be positive", path)
+	}
+	if backups < 0 {
+		return nil, fmt.Errorf("pipeline: rotating %s: the number of backups cannot be negative", path)
+	}
+	r := &RotatingFile{path: path, maxBytes: maxBytes, backups: backups}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.f, r.size = f, info.Size()
+	return r, nil
+}
+
+func (r *RotatingFile) Write(data string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return 0, r.closed()
+	}
+	if data == "" {
+		return 0, nil
+	}
+	if r.size > 0 && r.size+int64(len(data)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			r.err = err
+			return 0, err
+		}
+	}
+	n, err := r.f.WriteString(data)
+	r.size += int64(n)
+	return n, err
+}
+
+// closed returns the error of a write to a RotatingFile without a file
+func (r *RotatingFile) closed() error {
+	if r.err != nil {
+		return &rotateError{r.err}
+	}
+	return ErrClosed
+}
+
+// rotate moves the current file to path.1, shifting the older backups,
+// and starts an empty file. When it fails the current file is closed and
+// later writes fail.
+func (r *RotatingFile) rotate() error {
+	err := r.f.Close()
+	r.f = nil
+	if err != nil {
+		return err
+	}
+	if r.backups > 0 {
+		for i := r.backups - 1; i >= 1; i-- {
+			err = os.Rename(r.backup(i), r.backup(i+1))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		if err = os.Rename(r.path, r.backup(1)); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	r.f, r.size = f, 0
+	return nil
+}
+
+// backup returns the name of the i-th backup
+func (r *RotatingFile) backup(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+// Files returns the files written so far, oldest first: the backups that
+// exist, then the current file
+func (r *RotatingFile) Files() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var files []string
+	for i := r.backups; i >= 1; i-- {
+		if _, err := os.Stat(r.backup(i)); err == nil {
+			files = append(files, r.backup(i))
+		}
+	}
+	return append(files, r.path)
+}
+
+// Flush commits the file to disk
+func (r *RotatingFile) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return r.closed()
+	}
+	return r.f.Sync()
+}
+
+// Close closes the current file. Writes after Close fail with ErrClosed.
+// After a failed rotation there is no file left to close.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
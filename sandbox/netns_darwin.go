@@ -0,0 +1,8 @@
+package sandbox
+
+import "syscall"
+
+// isolateNetwork is not available without Linux namespaces
+func isolateNetwork(attr *syscall.SysProcAttr) bool {
+	return false
+}
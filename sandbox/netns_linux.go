@@ -0,0 +1,17 @@
+package sandbox
+
+import (
+	"os"
+	"syscall"
+)
+
+// isolateNetwork starts the program in new user and network namespaces.
+// The network namespace has only a loopback interface, which is down, so
+// the program cannot reach any other machine. The user namespace lets an
+// unprivileged user create it; the program keeps its user and group IDs.
+func isolateNetwork(attr *syscall.SysProcAttr) bool {
+	attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+	attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+	attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+	return true
+}
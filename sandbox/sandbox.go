@@ -0,0 +1,413 @@
+// Package sandbox compiles and runs learner-written Go programs with
+// guardrails: wall-clock and CPU timeouts, memory and output limits, a
+// private working directory, a scrubbed environment and, where Linux
+// namespaces are available, no network access.
+//
+// Limits are applied by a small helper: the explorer starts itself with a
+// special first argument, sets the resource limits and then replaces itself
+// with the program. Programs that use Run must call Helper first thing in
+// main.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits bounds what a sandboxed program may use
+type Limits struct {
+	WallTime time.Duration // real time before the program is killed
+	CPUTime  time.Duration // processor time, enforced by the kernel
+	Memory   uint64        // writable memory in bytes
+	Output   int64         // bytes of standard output and of standard error, and size of any file written
+	Network  bool          // allow network access instead of isolating the program
+}
+
+// DefaultLimits are generous for lesson-sized programs and small enough
+// that a runaway loop or allocation does not affect the machine
+func DefaultLimits() Limits {
+	return Limits{
+		WallTime: 10 * time.Second,
+		CPUTime:  5 * time.Second,
+		Memory:   512 << 20,
+		Output:   1 << 20,
+	}
+}
+
+// Result describes how a sandboxed program ended
+type Result struct {
+	ExitCode int    // -1 when the program was ended by a signal
+	Signal   string // the signal that ended the program, if any
+	Stdout   string
+	Stderr   string
+	Duration time.Duration // wall-clock time
+	CPU      time.Duration // user and system processor time
+
+	TimedOut      bool // killed after Limits.WallTime
+	CPUExceeded   bool // stopped by the kernel after Limits.CPUTime
+	OutputLimited bool // killed for writing more than Limits.Output
+	OutOfMemory   bool // the Go runtime could not allocate within Limits.Memory
+
+	NetworkIsolated bool
+	Warnings        []string
+}
+
+// Success reports whether the program ran to completion and exited with
+// status 0
+func (r *Result) Success() bool {
+	return r.ExitCode == 0 && !r.TimedOut && !r.OutputLimited
+}
+
+// Summary describes the outcome in one line, such as "exit status 0 in
+// 12ms" or "killed after the 10s time limit"
+func (r *Result) Summary() string {
+	d := r.Duration.Round(time.Millisecond)
+	switch {
+	case r.TimedOut:
+		return fmt.Sprintf("killed after the %s time limit", d.Round(time.Second))
+	case r.CPUExceeded:
+		return fmt.Sprintf("stopped after using up its CPU time (%s)", d)
+	case r.OutputLimited:
+		return fmt.Sprintf("killed for writing too much output (%s)", d)
+	case r.OutOfMemory:
+		return fmt.Sprintf("ran out of memory (%s)", d)
+	case r.Signal != "":
+		return fmt.Sprintf("ended by signal %q in %s", r.Signal, d)
+	default:
+		return fmt.Sprintf("exit status %d in %s", r.ExitCode, d)
+	}
+}
+
+// CompileError is returned when the program does not compile. Output holds
+// the compiler's messages with file names relative to the program.
+type CompileError struct {
+	Output string
+}
+
+func (e *CompileError) Error() string {
+	return "compilation failed:\n" + e.Output
+}
+
+// ErrUnsupported is returned on platforms where the limits cannot be
+// enforced
+var ErrUnsupported = errors.New("sandbox: running programs is not supported on this platform")
+
+// compileTimeout bounds how long the go command may take to build a
+// program
+const compileTimeout = 2 * time.Minute
+
+// Run compiles the source of a main package and runs it within the limits.
+// It returns an error when the program cannot be built or started; how the
+// program itself ended is reported in the Result.
+func Run(source []byte, limits Limits) (*Result, error) {
+	if !supported {
+		return nil, ErrUnsupported
+	}
+
+	dir, err := os.MkdirTemp("", "explorer-sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	binary, _, err := compile(dir, source)
+	if err != nil {
+		return nil, err
+	}
+
+	// The program gets a working directory of its own, separate from the
+	// one holding its source and binary
+	work := filepath.Join(dir, "work")
+	if err := os.Mkdir(work, 0o700); err != nil {
+		return nil, err
+	}
+	return execute(binary, work, limits)
+}
+
+// Build compiles the source of a main package without running it and
+// returns what the compiler reported. It is meant for compiler flags that
+// describe the code, such as "-m" for escape analysis, which are passed on
+// with -gcflags. Building does not need the helper and works everywhere the
+// go command does.
+func Build(source []byte, gcflags string) (string, error) {
+	dir, err := os.MkdirTemp("", "explorer-build-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	_, out, err := compile(dir, source, "-gcflags="+gcflags)
+	return out, err
+}
+
+// compile builds source into a binary inside dir, passing flags on to go
+// build, and returns the binary and the compiler's output
+func compile(dir string, source []byte, flags ...string) (string, string, error) {
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		return "", "", errors.New("sandbox: the go command is needed to compile programs")
+	}
+	src := filepath.Join(dir, "src")
+	if err := os.Mkdir(src, 0o700); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(filepath.Join(src, "go.mod"), []byte("module sandbox\n\ngo 1.19\n"), 0o600); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(filepath.Join(src, "main.go"), source, 0o600); err != nil {
+		return "", "", err
+	}
+
+	binary := filepath.Join(dir, "program")
+	ctx, cancel := context.WithTimeout(context.Background(), compileTimeout)
+	defer cancel()
+	args := append(append([]string{"build"}, flags...), "-o", binary, ".")
+	cmd := exec.CommandContext(ctx, goCmd, args...)
+	cmd.Dir = src
+	cmd.Env = buildEnv()
+	out, err := cmd.CombinedOutput()
+	output := cleanCompilerOutput(string(out), src)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", "", errors.New("sandbox: compiling took too long")
+		}
+		if _, ok := err.(*exec.ExitError); !ok {
+			return "", "", fmt.Errorf("sandbox: running go build: %w", err)
+		}
+		return "", "", &CompileError{Output: output}
+	}
+	return binary, output, nil
+}
+
+// buildEnv is the environment of the go command: enough to find the
+// toolchain and the build cache, with downloads turned off
+func buildEnv() []string {
+	env := []string{"GOPROXY=off", "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local", "CGO_ENABLED=0", "GO111MODULE=on"}
+	for _, name := range []string{"PATH", "HOME", "GOROOT", "GOPATH", "GOCACHE", "LOCALAPPDATA", "XDG_CACHE_HOME", "SYSTEMROOT", "TMPDIR"} {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+// cleanCompilerOutput drops the go command's package header and makes
+// file names relative to the program
+func cleanCompilerOutput(out, dir string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if strings.HasPrefix(line, "# ") {
+			continue
+		}
+		line = strings.ReplaceAll(line, dir+string(filepath.Separator), "")
+		lines = append(lines, strings.TrimPrefix(line, "./"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// programEnv is the scrubbed environment a program runs with. GOMEMLIMIT
+// makes the garbage collector work harder before the memory limit is hit.
+func programEnv(work string, limits Limits) []string {
+	return []string{
+		"GOMEMLIMIT=" + strconv.FormatUint(limits.Memory*9/10, 10),
+		"PATH=/usr/bin:/bin",
+		"HOME=" + work,
+		"TMPDIR=" + work,
+		"LANG=C.UTF-8",
+		"GOTRACEBACK=single",
+	}
+}
+
+// execute runs a compiled program through the helper
+func execute(binary, work string, limits Limits) (*Result, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: finding the helper: %w", err)
+	}
+
+	result := &Result{}
+	var stdout, stderr *limitedBuffer
+	var cmd *exec.Cmd
+
+	var mu sync.Mutex
+	kill := func(reason *bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		*reason = true
+		killGroup(cmd)
+	}
+	tooMuchOutput := func() { kill(&result.OutputLimited) }
+
+	start := func(isolate bool) error {
+		cmd = exec.Command(self, helperArgs(limits, binary)...)
+		cmd.Dir = work
+		cmd.Env = programEnv(work, limits)
+		stdout = &limitedBuffer{limit: limits.Output, exceeded: tooMuchOutput}
+		stderr = &limitedBuffer{limit: limits.Output, exceeded: tooMuchOutput}
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+		result.NetworkIsolated = prepare(cmd, isolate)
+		return cmd.Start()
+	}
+
+	began := time.Now()
+	err = start(!limits.Network)
+	if err != nil && result.NetworkIsolated {
+		// Namespaces can be disabled for unprivileged users; run without
+		// them rather than not at all, and say so
+		result.Warnings = append(result.Warnings, fmt.Sprintf("network access is not blocked: Linux namespaces are unavailable (%v)", err))
+		began = time.Now()
+		err = start(false)
+	} else if !limits.Network && !result.NetworkIsolated {
+		result.Warnings = append(result.Warnings, "network access is not blocked: this platform has no network namespaces")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: starting program: %w", err)
+	}
+
+	timer := time.AfterFunc(limits.WallTime, func() { kill(&result.TimedOut) })
+
+	waitErr := cmd.Wait()
+	timer.Stop()
+	// Children the program started are still in its process group
+	killGroup(cmd)
+	result.Duration = time.Since(began)
+
+	mu.Lock()
+	defer mu.Unlock()
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	if waitErr != nil {
+		if _, ok := waitErr.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("sandbox: waiting for program: %w", waitErr)
+		}
+	}
+	result.ExitCode = cmd.ProcessState.ExitCode()
+	result.CPU = cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+	// Go programs ignore SIGXCPU, so the kernel kills them at the hard
+	// limit a second later
+	result.Signal = exitSignal(cmd.ProcessState)
+	result.CPUExceeded = result.Signal != "" && !result.TimedOut && !result.OutputLimited && result.CPU >= limits.CPUTime
+	result.OutOfMemory = strings.Contains(result.Stderr, "out of memory")
+	if result.ExitCode == helperFailed && strings.HasPrefix(result.Stderr, helperPrefix) {
+		return nil, errors.New(strings.TrimSpace(result.Stderr))
+	}
+	return result, nil
+}
+
+// limitedBuffer keeps up to limit bytes and calls exceeded once when more
+// are written
+type limitedBuffer struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	limit    int64
+	over     bool
+	exceeded func()
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	room := b.limit - int64(b.buf.Len())
+	if int64(len(p)) <= room {
+		b.buf.Write(p)
+		b.mu.Unlock()
+		return len(p), nil
+	}
+	if room > 0 {
+		b.buf.Write(p[:room])
+	}
+	first := !b.over
+	b.over = true
+	b.mu.Unlock()
+	if first && b.exceeded != nil {
+		b.exceeded()
+	}
+	// Report success so the copy goroutine keeps draining the pipe until
+	// the program is gone
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// helperArg is the first argument that makes the explorer act as the
+// sandbox helper
+const helperArg = "__sandbox-exec"
+
+// helperFailed is the exit status of a helper that could not start the
+// program, and helperPrefix starts its error message
+const (
+	helperFailed = 125
+	helperPrefix = "sandbox helper: "
+)
+
+// helperArgs builds the command line that makes the helper apply the limits
+// and run binary
+func helperArgs(l Limits, binary string) []string {
+	cpu := int64((l.CPUTime + time.Second - 1) / time.Second)
+	return []string{
+		helperArg,
+		"cpu=" + strconv.FormatInt(cpu, 10),
+		"memory=" + strconv.FormatUint(l.Memory, 10),
+		"output=" + strconv.FormatInt(l.Output, 10),
+		"--", binary,
+	}
+}
+
+// Helper must be called at the start of main by programs that use Run.
+// When the process was started as the sandbox helper it applies the limits
+// and replaces itself with the program, never returning; otherwise it
+// returns immediately.
+func Helper() {
+	if len(os.Args) < 2 || os.Args[1] != helperArg {
+		return
+	}
+	fail := func(err error) {
+		fmt.Fprintln(os.Stderr, helperPrefix+err.Error())
+		os.Exit(helperFailed)
+	}
+
+	var limits helperLimits
+	args := os.Args[2:]
+	for len(args) > 0 && args[0] != "--" {
+		key, value, _ := strings.Cut(args[0], "=")
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			fail(fmt.Errorf("bad limit %q", args[0]))
+		}
+		switch key {
+		case "cpu":
+			limits.cpu = n
+		case "memory":
+			limits.memory = n
+		case "output":
+			limits.output = n
+		default:
+			fail(fmt.Errorf("unknown limit %q", key))
+		}
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		fail(errors.New("expected -- and the program to run"))
+	}
+	if err := applyLimits(limits); err != nil {
+		fail(err)
+	}
+	fail(execProgram(args[1]))
+}
+
+// helperLimits are the limits as the helper receives them: seconds and bytes
+type helperLimits struct {
+	cpu, memory, output uint64
+}
@@ -0,0 +1,26 @@
+//go:build !(linux || darwin)
+
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Resource limits and process groups are not available on this platform,
+// so Run refuses to start programs
+const supported = false
+
+func prepare(cmd *exec.Cmd, isolate bool) bool { return false }
+
+func killGroup(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		cmd.Process.Kill()
+	}
+}
+
+func exitSignal(state *os.ProcessState) string { return "" }
+
+func applyLimits(l helperLimits) error { return ErrUnsupported }
+
+func execProgram(path string) error { return ErrUnsupported }
@@ -0,0 +1,74 @@
+//go:build linux || darwin
+
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const supported = true
+
+// prepare starts the program in a process group of its own, so it can be
+// killed together with any processes it starts, and isolates its network
+// when asked and possible. It reports whether the network is isolated.
+func prepare(cmd *exec.Cmd, isolate bool) bool {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if isolate {
+		return isolateNetwork(cmd.SysProcAttr)
+	}
+	return false
+}
+
+// killGroup kills the program's whole process group
+func killGroup(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// exitSignal returns the name of the signal that ended the process
+func exitSignal(state *os.ProcessState) string {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+	return status.Signal().String()
+}
+
+// applyLimits sets the resource limits of the helper, which the program
+// inherits when the helper replaces itself with it
+func applyLimits(l helperLimits) error {
+	limits := []struct {
+		name     string
+		resource int
+		soft     uint64
+		hard     uint64
+	}{
+		// SIGXCPU at the limit, SIGKILL a second later if it is ignored
+		{"CPU time", syscall.RLIMIT_CPU, l.cpu, l.cpu + 1},
+		// The Go runtime reserves far more address space than it uses, so
+		// memory is limited by the data segment, which only counts the
+		// writable memory a program has actually mapped
+		{"memory", syscall.RLIMIT_DATA, l.memory, l.memory},
+		{"file size", syscall.RLIMIT_FSIZE, l.output, l.output},
+		{"core dumps", syscall.RLIMIT_CORE, 0, 0},
+	}
+	for _, limit := range limits {
+		if limit.soft == 0 && limit.resource != syscall.RLIMIT_CORE {
+			continue
+		}
+		rlimit := syscall.Rlimit{Cur: limit.soft, Max: limit.hard}
+		if err := syscall.Setrlimit(limit.resource, &rlimit); err != nil {
+			return fmt.Errorf("setting the %s limit: %w", limit.name, err)
+		}
+	}
+	return nil
+}
+
+// execProgram replaces the helper with the program
+func execProgram(path string) error {
+	return syscall.Exec(path, []string{path}, os.Environ())
+}
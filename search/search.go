@@ -0,0 +1,273 @@
+// Package search is a full-text index over the lessons. Words are indexed
+// whole, without stemming or splitting, so identifiers such as iota,
+// Stringer and ReadWriter can be found exactly as they are written.
+package search
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-interface-enum-explorer/lessons"
+	"go-interface-enum-explorer/utils"
+)
+
+// Field is the part of a lesson a word was found in
+type Field int
+
+const (
+	FieldTitle Field = iota
+	FieldExplanation
+	FieldTakeaways
+	FieldCode
+)
+
+// weight says how much a match in the field counts towards the score
+func (f Field) weight() float64 {
+	switch f {
+	case FieldTitle:
+		return 4
+	case FieldExplanation, FieldTakeaways:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// wordPattern matches the units that are indexed: identifiers and numbers
+var wordPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*|[0-9]+`)
+
+// Words splits text into the words the index is built from
+func Words(text string) []string {
+	return wordPattern.FindAllString(text, -1)
+}
+
+// posting records how often a word occurs in one field of one lesson
+type posting struct {
+	doc   int
+	field Field
+	count int
+	// exact holds the number of occurrences with each spelling, so a query
+	// for "Stringer" ranks that spelling above "stringer"
+	exact map[string]int
+}
+
+// document is one indexed lesson
+type document struct {
+	lesson *lessons.Lesson
+	lines  []line
+}
+
+// line is a line of lesson text kept for building snippets
+type line struct {
+	field Field
+	text  string
+}
+
+// Index is an inverted index from lowercased words to the lessons that
+// contain them
+type Index struct {
+	docs  []document
+	terms map[string][]posting
+}
+
+// Result is one lesson matching a query
+type Result struct {
+	Lesson   *lessons.Lesson
+	Score    float64
+	Matched  int
+	Snippets []Snippet
+}
+
+// Snippet is a line of a matching lesson with the positions of the matched
+// words, so callers can highlight them
+type Snippet struct {
+	Field   Field
+	Text    string
+	Matches [][2]int
+}
+
+// Build indexes the title, explanation, code and takeaways of every lesson
+func Build(all []*lessons.Lesson) *Index {
+	ix := &Index{terms: make(map[string][]posting)}
+	for i, l := range all {
+		doc := document{lesson: l}
+		fields := []struct {
+			field Field
+			text  string
+		}{
+			{FieldTitle, l.Title},
+			{FieldExplanation, l.Section(utils.SectionExplanation)},
+			{FieldTakeaways, l.Section(utils.SectionTakeaways)},
+			{FieldCode, l.Section(utils.SectionCode)},
+		}
+		for _, f := range fields {
+			ix.add(i, f.field, f.text)
+			for _, text := range strings.Split(f.text, "\n") {
+				if strings.TrimSpace(text) != "" {
+					doc.lines = append(doc.lines, line{field: f.field, text: strings.TrimSpace(text)})
+				}
+			}
+		}
+		ix.docs = append(ix.docs, doc)
+	}
+	return ix
+}
+
+func (ix *Index) add(doc int, field Field, text string) {
+	counts := make(map[string]*posting)
+	for _, word := range Words(text) {
+		term := strings.ToLower(word)
+		p := counts[term]
+		if p == nil {
+			p = &posting{doc: doc, field: field, exact: make(map[string]int)}
+			counts[term] = p
+		}
+		p.count++
+		p.exact[word]++
+	}
+	for term, p := range counts {
+		ix.terms[term] = append(ix.terms[term], *p)
+	}
+}
+
+// Search returns the lessons matching the query, best first. Lessons that
+// contain every word of the query rank above lessons that contain only
+// some. Within that, words that are rare across lessons, matches in titles
+// and matches with the same capitalization as the query count more.
+func (ix *Index) Search(query string, limit int) []Result {
+	words := Words(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	scores := make(map[int]float64)
+	matched := make(map[int]map[string]bool)
+	for _, word := range words {
+		term := strings.ToLower(word)
+		postings := ix.terms[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		docsWithTerm := make(map[int]bool)
+		for _, p := range postings {
+			docsWithTerm[p.doc] = true
+		}
+		idf := math.Log(1 + float64(len(ix.docs))/float64(len(docsWithTerm)))
+
+		for _, p := range postings {
+			score := p.field.weight() * (1 + math.Log(float64(p.count))) * idf
+			if p.exact[word] > 0 {
+				score *= 1.5
+			}
+			scores[p.doc] += score
+			if matched[p.doc] == nil {
+				matched[p.doc] = make(map[string]bool)
+			}
+			matched[p.doc][term] = true
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for doc, score := range scores {
+		results = append(results, Result{
+			Lesson:   ix.docs[doc].lesson,
+			Score:    score,
+			Matched:  len(matched[doc]),
+			Snippets: ix.snippets(doc, words, 3),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Matched != results[j].Matched {
+			return results[i].Matched > results[j].Matched
+		}
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Lesson.Title < results[j].Lesson.Title
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// snippets picks the lines of a lesson that match the most query words,
+// preferring prose over code
+func (ix *Index) snippets(doc int, words []string, max int) []Snippet {
+	terms := make(map[string]bool)
+	for _, w := range words {
+		terms[strings.ToLower(w)] = true
+	}
+
+	type candidate struct {
+		snippet Snippet
+		found   int
+		order   int
+	}
+	var candidates []candidate
+	for i, l := range ix.docs[doc].lines {
+		if l.field == FieldTitle {
+			continue
+		}
+		s := Snippet{Field: l.field, Text: l.text}
+		found := make(map[string]bool)
+		for _, loc := range wordPattern.FindAllStringIndex(l.text, -1) {
+			term := strings.ToLower(l.text[loc[0]:loc[1]])
+			if terms[term] {
+				s.Matches = append(s.Matches, [2]int{loc[0], loc[1]})
+				found[term] = true
+			}
+		}
+		if len(s.Matches) > 0 {
+			candidates = append(candidates, candidate{snippet: s, found: len(found), order: i})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.found != b.found {
+			return a.found > b.found
+		}
+		if (a.snippet.Field == FieldCode) != (b.snippet.Field == FieldCode) {
+			return b.snippet.Field == FieldCode
+		}
+		return a.order < b.order
+	})
+
+	var snippets []Snippet
+	for i := 0; i < len(candidates) && i < max; i++ {
+		snippets = append(snippets, candidates[i].snippet)
+	}
+	return snippets
+}
+
+// Highlight returns the snippet text with every match passed through mark
+func (s Snippet) Highlight(mark func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range s.Matches {
+		b.WriteString(s.Text[last:m[0]])
+		b.WriteString(mark(s.Text[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(s.Text[last:])
+	return b.String()
+}
+
+// Name returns a short label for the field, used when showing snippets
+func (f Field) Name() string {
+	switch f {
+	case FieldTitle:
+		return "title"
+	case FieldExplanation:
+		return "explanation"
+	case FieldTakeaways:
+		return "takeaways"
+	default:
+		return "code"
+	}
+}
@@ -0,0 +1,79 @@
+// Package settings reads and writes the explorer's settings file, a JSON
+// document in the user's configuration directory.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Settings are the preferences kept between sessions. Empty strings mean
+// the built-in default.
+type Settings struct {
+	// Theme names the color theme: dark, light, high-contrast or monochrome
+	Theme string `json:"theme"`
+	// Pager is "builtin", "off", or a command such as "less -R"
+	Pager string `json:"pager"`
+	// OutputFormat is the export format used when none is given
+	OutputFormat string `json:"output_format"`
+	// Language selects the interface language; $LANG is used when it is
+	// empty
+	Language    string `json:"language"`
+	Accessible  bool   `json:"accessible"`
+	LineNumbers bool   `json:"line_numbers"`
+}
+
+// Defaults returns the settings used when there is no settings file
+func Defaults() *Settings {
+	return &Settings{
+		Theme:        "dark",
+		Pager:        "builtin",
+		OutputFormat: "html",
+	}
+}
+
+// Path returns where the settings file is kept, such as
+// ~/.config/go-interface-enum-explorer/settings.json on Linux
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-interface-enum-explorer", "settings.json"), nil
+}
+
+// Load reads the settings file at path. A missing file is not an error and
+// yields the defaults; fields missing from the file keep their defaults.
+func Load(path string) (*Settings, error) {
+	s := Defaults()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("reading settings from %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the settings to path, creating its directory if needed
+func (s *Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("saving settings: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("saving settings: %w", err)
+	}
+	return nil
+}
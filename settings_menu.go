@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-interface-enum-explorer/i18n"
+	"go-interface-enum-explorer/settings"
+	"go-interface-enum-explorer/utils"
+)
+
+// applySettings puts settings into effect for the rest of the session
+func applySettings(s *settings.Settings) error {
+	lang := s.Language
+	if lang == "" {
+		lang = i18n.FromEnvironment()
+	}
+	i18n.SetLanguage(lang)
+
+	utils.SetColors(!noColor && os.Getenv("NO_COLOR") == "")
+	utils.SetAccessible(s.Accessible)
+	utils.SetLineNumbers(s.LineNumbers)
+	utils.SetPager(s.Pager)
+	return utils.SetTheme(s.Theme)
+}
+
+// editSettings shows the settings menu. Every change takes effect at once
+// and is saved to the settings file.
+func editSettings(scanner *bufio.Scanner) {
+	status := ""
+	for {
+		clearScreen()
+		utils.PrintColoredTitle(i18n.T("settings.title"), utils.ColorGreen)
+		if settingsPath != "" {
+			fmt.Println(i18n.T("settings.file", settingsPath))
+		}
+		if status != "" {
+			fmt.Println(status)
+		}
+		fmt.Println()
+
+		fmt.Printf("1. %s: %s\n", i18n.T("settings.theme"), prefs.Theme)
+		fmt.Printf("2. %s: %s\n", i18n.T("settings.pager"), settingValue(prefs.Pager))
+		fmt.Printf("3. %s: %s\n", i18n.T("settings.format"), prefs.OutputFormat)
+		fmt.Printf("4. %s: %s\n", i18n.T("settings.language"), settingValue(prefs.Language))
+		fmt.Printf("5. %s: %s\n", i18n.T("settings.accessible"), onOff(prefs.Accessible))
+		fmt.Printf("6. %s: %s\n", i18n.T("settings.line_numbers"), onOff(prefs.LineNumbers))
+		fmt.Println("b. " + i18n.T("menu.back"))
+
+		fmt.Print("\n" + i18n.T("prompt.setting"))
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
+
+		if handleSearch(scanner, choice) {
+			continue
+		}
+		language := prefs.Language
+		switch choice {
+		case "b", "B":
+			return
+		case "1":
+			var names []string
+			for _, t := range utils.Themes {
+				names = append(names, t.Name)
+			}
+			prefs.Theme = chooseSetting(scanner, names, prefs.Theme)
+		case "2":
+			prefs.Pager = promptSetting(scanner, i18n.T("settings.pager_help"))
+		case "3":
+			var formats []string
+			for name := range exportFormats {
+				formats = append(formats, name)
+			}
+			sort.Strings(formats)
+			prefs.OutputFormat = chooseSetting(scanner, formats, prefs.OutputFormat)
+		case "4":
+			prefs.Language = chooseSetting(scanner, i18n.Languages(), prefs.Language)
+		case "5":
+			prefs.Accessible = !prefs.Accessible
+		case "6":
+			prefs.LineNumbers = !prefs.LineNumbers
+		default:
+			status = i18n.T("error.invalid_choice")
+			continue
+		}
+
+		if err := applySettings(prefs); err != nil {
+			status = err.Error()
+			continue
+		}
+		if prefs.Language != language {
+			// Lessons are searched in the language they are shown in, so
+			// the next search builds the index again
+			searchIndex = nil
+		}
+		status = saveSettings()
+	}
+}
+
+// saveSettings writes the settings file and returns a message saying so
+func saveSettings() string {
+	if settingsPath == "" {
+		return i18n.T("settings.not_saved")
+	}
+	if err := prefs.Save(settingsPath); err != nil {
+		return err.Error()
+	}
+	return i18n.T("settings.saved")
+}
+
+// chooseSetting lets the learner pick one of the options by number and
+// returns current when they pick none
+func chooseSetting(scanner *bufio.Scanner, options []string, current string) string {
+	fmt.Println()
+	for i, option := range options {
+		fmt.Printf("%d. %s\n", i+1, option)
+	}
+	fmt.Print("\n" + i18n.T("prompt.option"))
+	scanner.Scan()
+	index, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil || index < 1 || index > len(options) {
+		return current
+	}
+	return options[index-1]
+}
+
+// promptSetting reads a free-form setting; an empty answer restores the
+// default
+func promptSetting(scanner *bufio.Scanner, help string) string {
+	fmt.Println("\n" + help)
+	fmt.Print(i18n.T("prompt.value"))
+	scanner.Scan()
+	return strings.TrimSpace(scanner.Text())
+}
+
+func settingValue(value string) string {
+	if value == "" {
+		return i18n.T("settings.default")
+	}
+	return value
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return i18n.T("settings.on")
+	}
+	return i18n.T("settings.off")
+}
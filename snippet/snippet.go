@@ -0,0 +1,133 @@
+// Package snippet turns the code shown in a lesson into a complete Go
+// program. Lesson code leaves out the package clause and imports to stay
+// short, so they are reconstructed here when the code needs to be compiled
+// or run.
+package snippet
+
+import (
+	"go/scanner"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// stdPackages maps the package names lesson code refers to onto their
+// import paths
+var stdPackages = map[string]string{
+	"bufio":   "bufio",
+	"bytes":   "bytes",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"io":      "io",
+	"json":    "encoding/json",
+	"log":     "log",
+	"math":    "math",
+	"os":      "os",
+	"rand":    "math/rand",
+	"reflect": "reflect",
+	"regexp":  "regexp",
+	"sort":    "sort",
+	"strconv": "strconv",
+	"strings": "strings",
+	"sync":    "sync",
+	"time":    "time",
+	"unsafe":  "unsafe",
+}
+
+// Imports returns the import paths of the standard library packages the
+// code refers to, such as "fmt" for fmt.Println, in sorted order
+func Imports(code string) []string {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(code))
+
+	var s scanner.Scanner
+	s.Init(file, []byte(code), func(token.Position, string) {}, 0)
+
+	// Names declared by the snippet itself (such as a variable called
+	// "time") shadow the package and must not be imported
+	declared := make(map[string]bool)
+	used := make(map[string]bool)
+
+	var prev, prevPrev token.Token
+	var prevLit string
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.PERIOD && prev == token.IDENT && prevPrev != token.PERIOD {
+			if _, ok := stdPackages[prevLit]; ok {
+				used[prevLit] = true
+			}
+		}
+		if tok == token.DEFINE && prev == token.IDENT {
+			declared[prevLit] = true
+		}
+		prevPrev, prev, prevLit = prev, tok, lit
+	}
+
+	var paths []string
+	for name := range used {
+		if !declared[name] {
+			paths = append(paths, stdPackages[name])
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ImportBlock returns an import declaration for the given paths, or an
+// empty string when there are none
+func ImportBlock(paths []string) string {
+	switch len(paths) {
+	case 0:
+		return ""
+	case 1:
+		return "import \"" + paths[0] + "\"\n"
+	}
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, p := range paths {
+		b.WriteString("\t\"" + p + "\"\n")
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// Program returns lesson code as a complete main package: a package clause
+// and the imports the code needs, followed by the code. Code that already
+// starts with a package clause is returned unchanged.
+func Program(code string) string {
+	if hasPackageClause(code) {
+		return code
+	}
+	return header(code) + strings.Trim(code, "\n") + "\n"
+}
+
+// Offset returns the number of lines Program adds before the code, so that
+// line n of the program is line n-Offset of the code. Compiler messages
+// about a program are mapped back to the lesson code with it.
+func Offset(code string) int {
+	if hasPackageClause(code) {
+		return 0
+	}
+	leading := len(code) - len(strings.TrimLeft(code, "\n"))
+	return strings.Count(header(code), "\n") - leading
+}
+
+// header is the package clause and imports Program puts before the code
+func header(code string) string {
+	return "package main\n\n" + ImportBlock(Imports(code)) + "\n"
+}
+
+// hasPackageClause reports whether the first token of the code, after any
+// comments, is the package keyword
+func hasPackageClause(code string) bool {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(code))
+
+	var s scanner.Scanner
+	s.Init(file, []byte(code), func(token.Position, string) {}, 0)
+	_, tok, _ := s.Scan()
+	return tok == token.PACKAGE
+}
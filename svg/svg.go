@@ -0,0 +1,122 @@
+// Package svg draws collections of shapes as an SVG image. It knows
+// nothing about any particular shape: a shape is drawn when it can describe
+// itself as an SVG element and say where it is, which the package finds
+// out with type assertions, so shapes of any package can be drawn.
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Drawer is a shape that can describe itself as an SVG element, such as a
+// <polygon> or a <circle>, in its own coordinates with y pointing up
+type Drawer interface {
+	SVGElement() string
+}
+
+// Bounder is a shape that knows the smallest rectangle containing it
+type Bounder interface {
+	Bounds() (minX, minY, maxX, maxY float64)
+}
+
+// Width is the width of the image in pixels; the height follows from the
+// shapes
+const Width = 480
+
+// margin is the space around the shapes, as a fraction of their extent
+const margin = 0.05
+
+// palette holds the fill colors, used in turn
+var palette = []string{"#4e79a7", "#f28e2b", "#59a14f", "#e15759", "#76b7b2", "#edc948", "#b07aa1"}
+
+// Render writes the shapes as an SVG document. Shapes that are not both a
+// Drawer and a Bounder cannot be placed, so they are left out; their
+// positions in shapes are returned.
+func Render[S any](w io.Writer, shapes []S) (skipped []int, err error) {
+	var elements []string
+	var titles []string
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i, s := range shapes {
+		d, ok := interface{}(s).(Drawer)
+		b, ok2 := interface{}(s).(Bounder)
+		if !ok || !ok2 {
+			skipped = append(skipped, i)
+			continue
+		}
+		x0, y0, x1, y1 := b.Bounds()
+		minX, minY = math.Min(minX, x0), math.Min(minY, y0)
+		maxX, maxY = math.Max(maxX, x1), math.Max(maxY, y1)
+		elements = append(elements, d.SVGElement())
+		titles = append(titles, fmt.Sprintf("%T", s))
+	}
+	if len(elements) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 1, 1
+	}
+
+	// The extent is padded, and never zero, so a lone point or line still
+	// gets a valid view box
+	m := margin * math.Max(math.Max(maxX-minX, maxY-minY), 1)
+	minX, minY, maxX, maxY = minX-m, minY-m, maxX+m, maxY+m
+	height := math.Round(Width * (maxY - minY) / (maxX - minX))
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%s\" viewBox=\"%s %s %s %s\">\n",
+		Width, Number(height), Number(minX), Number(-maxY), Number(maxX-minX), Number(maxY-minY))
+	// Shapes use y pointing up, SVG y pointing down. The stroke is about
+	// 1.5 pixels wide whatever the scale.
+	fmt.Fprintf(&b, "<g transform=\"scale(1,-1)\" stroke=\"#333\" stroke-width=\"%s\" fill-opacity=\"0.8\">\n",
+		Number(1.5*(maxX-minX)/Width))
+	for i, e := range elements {
+		fmt.Fprintf(&b, "<g fill=\"%s\"><title>%s</title>%s</g>\n", palette[i%len(palette)], html.EscapeString(titles[i]), e)
+	}
+	b.WriteString("</g>\n</svg>\n")
+	_, err = w.Write(b.Bytes())
+	return skipped, err
+}
+
+// WriteFile renders the shapes to the SVG file at path
+func WriteFile[S any](path string, shapes []S) (skipped []int, err error) {
+	var b bytes.Buffer
+	skipped, err = Render(&b, shapes)
+	if err != nil {
+		return skipped, err
+	}
+	return skipped, os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// Polygon returns a <polygon> element through the points, given as x, y
+// pairs
+func Polygon(xy ...float64) string {
+	coords := make([]string, 0, len(xy)/2)
+	for i := 0; i+1 < len(xy); i += 2 {
+		coords = append(coords, Number(xy[i])+","+Number(xy[i+1]))
+	}
+	return `<polygon points="` + strings.Join(coords, " ") + `"/>`
+}
+
+// Circle returns a <circle> element
+func Circle(cx, cy, r float64) string {
+	return fmt.Sprintf(`<circle cx="%s" cy="%s" r="%s"/>`, Number(cx), Number(cy), Number(r))
+}
+
+// Rect returns a <rect> element with its lower left corner at x, y
+func Rect(x, y, width, height float64) string {
+	return fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s"/>`, Number(x), Number(y), Number(width), Number(height))
+}
+
+// Number formats a coordinate without needless digits
+func Number(v float64) string {
+	v = math.Round(v*1000) / 1000
+	if v == 0 {
+		v = 0 // not -0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
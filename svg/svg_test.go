@@ -0,0 +1,116 @@
+package svg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// box is a shape with bounds and an element
+type box struct{ x, y, w, h float64 }
+
+func (b box) Bounds() (minX, minY, maxX, maxY float64) { return b.x, b.y, b.x + b.w, b.y + b.h }
+func (b box) SVGElement() string                       { return Rect(b.x, b.y, b.w, b.h) }
+
+// dot can be drawn but does not say where it is
+type dot struct{}
+
+func (dot) SVGElement() string { return Circle(0, 0, 1) }
+
+func TestRender(t *testing.T) {
+	var b strings.Builder
+	skipped, err := Render(&b, []interface{}{box{0, 0, 10, 5}, dot{}, "not a shape", box{2, 1, 1, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+
+	// The shapes span 10 by 5; a margin of 0.5 makes the view box 11 by 6
+	// and the image 480 by round(480*6/11) pixels. y is flipped, so the
+	// view box starts at -maxY.
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="480" height="262" viewBox="-0.5 -5.5 11 6">
+<g transform="scale(1,-1)" stroke="#333" stroke-width="0.034" fill-opacity="0.8">
+<g fill="#4e79a7"><title>svg.box</title><rect x="0" y="0" width="10" height="5"/></g>
+<g fill="#f28e2b"><title>svg.box</title><rect x="2" y="1" width="1" height="1"/></g>
+</g>
+</svg>
+`
+	if got := b.String(); got != want {
+		t.Errorf("Render wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderNothing(t *testing.T) {
+	var b strings.Builder
+	skipped, err := Render(&b, []dot{{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(skipped, []int{0}) {
+		t.Errorf("skipped = %v, want [0]", skipped)
+	}
+	// Without shapes the image still gets a valid, non-empty view box
+	if !strings.Contains(b.String(), `viewBox="-0.05 -1.05 1.1 1.1"`) || strings.Contains(b.String(), "<title>") {
+		t.Errorf("Render of no drawable shapes wrote\n%s", b.String())
+	}
+}
+
+func TestRenderEscapesTitles(t *testing.T) {
+	// The name of an anonymous struct type holds the quotes of its tags
+	tagged := struct {
+		box
+		Name string `json:"name"`
+	}{box: box{0, 0, 1, 1}}
+	var b strings.Builder
+	if _, err := Render(&b, []interface{}{tagged}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<title>struct { svg.box; Name string &#34;json:\&#34;name\&#34;&#34; }</title>`; !strings.Contains(b.String(), want) {
+		t.Errorf("Render wrote\n%s\nwant the title %s", b.String(), want)
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+var errWrite = errors.New("disk full")
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestRenderWriteError(t *testing.T) {
+	if _, err := Render(failingWriter{}, []box{{0, 0, 1, 1}}); !errors.Is(err, errWrite) {
+		t.Errorf("Render = %v, want the writer's error", err)
+	}
+}
+
+func TestElements(t *testing.T) {
+	for _, tc := range []struct{ got, want string }{
+		{Polygon(0, 0, 4, 0, 2, 3.5), `<polygon points="0,0 4,0 2,3.5"/>`},
+		{Polygon(0, 0, 1), `<polygon points="0,0"/>`},
+		{Circle(9, 2, 3), `<circle cx="9" cy="2" r="3"/>`},
+		{Rect(-1, 2, 0.5, 4), `<rect x="-1" y="2" width="0.5" height="4"/>`},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("got %s, want %s", tc.got, tc.want)
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for v, want := range map[float64]string{
+		3:          "3",
+		-2.5:       "-2.5",
+		1.23456:    "1.235",
+		-0.0001:    "0",
+		1e6:        "1000000",
+		2.0000001:  "2",
+		-1.9999999: "-2",
+	} {
+		if got := Number(v); got != want {
+			t.Errorf("Number(%g) = %q, want %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"strings"
+
+	"go-interface-enum-explorer/i18n"
+)
+
+// accessible selects the rendering meant for screen readers, changed
+// through SetAccessible
+var accessible = false
+
+// SetAccessible switches to a rendering that reads well with a screen
+// reader: sections are announced in words with their length, decorative
+// rules and colors are left out, code lines are read with their line
+// numbers and long lessons are printed in one piece instead of paged.
+func SetAccessible(enabled bool) {
+	accessible = enabled
+	if enabled {
+		colorsEnabled = false
+	}
+}
+
+// Accessible reports whether the screen reader rendering is selected
+func Accessible() bool {
+	return accessible
+}
+
+// SpokenName returns the name of the section kind as it is announced, such
+// as "Code example"
+func (k SectionKind) SpokenName() string {
+	return i18n.T("section.name." + k.Name())
+}
+
+// Announcement returns the sentence that introduces a section of the given
+// length, such as "Section: Code example, 42 lines"
+func (k SectionKind) Announcement(lines int) string {
+	if lines == 1 {
+		return i18n.T("a11y.section_one", k.SpokenName())
+	}
+	return i18n.T("a11y.section", k.SpokenName(), lines)
+}
+
+// isRule reports whether a line is only decoration, such as the "====="
+// lines under headings
+func isRule(line string) bool {
+	line = strings.TrimSpace(line)
+	return len(line) >= 3 && strings.Trim(line, "=-*_") == ""
+}
+
+// accessibleLines returns the lines of a section as a screen reader should
+// hear them: code with line numbers, other text without rules
+func accessibleLines(s Section) []string {
+	if s.Kind == SectionCode {
+		return codeLines(s, true)
+	}
+	var lines []string
+	for _, line := range strings.Split(StripANSI(s.Text), "\n") {
+		if !isRule(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// renderAccessible builds the screen reader version of a lesson document
+func renderAccessible(title string, sections []Section) *Document {
+	doc := &Document{Anchors: make(map[SectionKind]int)}
+	doc.add(title, "")
+	for _, s := range sections {
+		if _, seen := doc.Anchors[s.Kind]; !seen {
+			doc.Anchors[s.Kind] = len(doc.Lines)
+		}
+		lines := accessibleLines(s)
+		doc.add(s.Kind.Announcement(len(lines)))
+		doc.add(lines...)
+		doc.add("")
+	}
+	doc.add(i18n.T("a11y.end"))
+	return doc
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-interface-enum-explorer/i18n"
+)
+
+// Callout is a numbered marker on a line of lesson code. Lesson code marks a
+// line by ending its comment with the number in angle brackets, either on
+// its own ("// <1>") or after the comment text ("// wraps a Writer <1>"),
+// and the explanation and takeaways refer to that line by writing <1>.
+type Callout struct {
+	Number int
+	Line   int    // line of the code section, counting from 1
+	Code   string // the line without its marker
+}
+
+// calloutMarker matches a line whose last comment ends in a callout number
+var calloutMarker = regexp.MustCompile(`^(.*)//(.*?)\s*<(\d+)>\s*$`)
+
+// calloutRef matches a reference to a callout in prose
+var calloutRef = regexp.MustCompile(`<(\d+)>`)
+
+// StripCallouts removes the callout markers from code and returns the
+// callouts in line order. A comment that only held the marker is removed
+// with it.
+func StripCallouts(code string) (string, []Callout) {
+	lines := strings.Split(code, "\n")
+	var callouts []Callout
+	for i, line := range lines {
+		m := calloutMarker.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if strings.TrimSpace(m[2]) == "" {
+			line = strings.TrimRight(m[1], " \t")
+		} else {
+			line = m[1] + "//" + strings.TrimRight(m[2], " \t")
+		}
+		lines[i] = line
+		n, _ := strconv.Atoi(m[3])
+		callouts = append(callouts, Callout{Number: n, Line: i + 1, Code: strings.TrimSpace(line)})
+	}
+	return strings.Join(lines, "\n"), callouts
+}
+
+// ExtractCallouts returns a copy of the sections with the markers stripped
+// from the code, each code section keeping its own callouts, together with
+// every callout of the lesson
+func ExtractCallouts(sections []Section) ([]Section, []Callout) {
+	out := make([]Section, len(sections))
+	var all []Callout
+	for i, s := range sections {
+		if s.Kind == SectionCode {
+			s.Text, s.Callouts = StripCallouts(s.Text)
+			all = append(all, s.Callouts...)
+		}
+		out[i] = s
+	}
+	return out, all
+}
+
+// ReplaceCalloutRefs rewrites every <n> in text that names one of the
+// callouts with ref. References to numbers the code does not mark are left
+// as they are.
+func ReplaceCalloutRefs(text string, callouts []Callout, ref func(Callout) string) string {
+	if len(callouts) == 0 {
+		return text
+	}
+	return calloutRef.ReplaceAllStringFunc(text, func(match string) string {
+		n, _ := strconv.Atoi(match[1 : len(match)-1])
+		for _, c := range callouts {
+			if c.Number == n {
+				return ref(c)
+			}
+		}
+		return match
+	})
+}
+
+// CalloutBadge returns the symbol a callout is shown with: a circled
+// number such as ② up to 20 and the number in parentheses above that
+func CalloutBadge(n int) string {
+	if n >= 1 && n <= 20 {
+		return string(rune('①' + n - 1))
+	}
+	return "(" + strconv.Itoa(n) + ")"
+}
+
+// calloutMark is appended to a highlighted code line that has a callout
+func calloutMark(c Callout) string {
+	if accessible {
+		return " " + i18n.T("a11y.callout", c.Number)
+	}
+	return "  " + Colorize(ColorCyan, CalloutBadge(c.Number))
+}
+
+// calloutText replaces a reference in the explanation or takeaways
+func calloutText(c Callout) string {
+	if accessible {
+		return i18n.T("a11y.callout_ref", c.Number, c.Line)
+	}
+	return Colorize(ColorCyan, CalloutBadge(c.Number)) + " " + Colorize(ColorGray, i18n.T("callout.line", c.Line))
+}
+
+// resolveCallouts prepares a lesson's sections for the terminal: markers
+// are stripped from the code and references in the prose point at the line
+func resolveCallouts(sections []Section) []Section {
+	sections, callouts := ExtractCallouts(sections)
+	for i, s := range sections {
+		if s.Kind == SectionExplanation || s.Kind == SectionTakeaways {
+			sections[i].Text = ReplaceCalloutRefs(s.Text, callouts, calloutText)
+		}
+	}
+	return sections
+}
+
+// codeLines highlights a code section and marks the lines with a callout
+func codeLines(s Section, lineNumbers bool) []string {
+	lines := HighlightCode(s.Text, lineNumbers)
+	for _, c := range s.Callouts {
+		if c.Line >= 1 && c.Line <= len(lines) {
+			lines[c.Line-1] += calloutMark(c)
+		}
+	}
+	return lines
+}
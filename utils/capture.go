@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-interface-enum-explorer/i18n"
+)
+
+// SectionKind identifies one of the blocks a lesson is made of
+type SectionKind int
+
+const (
+	SectionExplanation SectionKind = iota
+	SectionCode
+	SectionOutput
+	SectionTakeaways
+)
+
+// Name returns the lowercase name of the section kind, used in message IDs
+// and CSS classes
+func (k SectionKind) Name() string {
+	switch k {
+	case SectionExplanation:
+		return "explanation"
+	case SectionCode:
+		return "code"
+	case SectionOutput:
+		return "output"
+	case SectionTakeaways:
+		return "takeaways"
+	default:
+		return "other"
+	}
+}
+
+// Title returns the heading printed above a section, in the selected
+// language
+func (k SectionKind) Title() string {
+	return i18n.T("section." + k.Name())
+}
+
+// Color returns the color used for the section heading
+func (k SectionKind) Color() string {
+	switch k {
+	case SectionExplanation:
+		return ColorBlue
+	case SectionCode:
+		return ColorGreen
+	case SectionOutput:
+		return ColorYellow
+	case SectionTakeaways:
+		return ColorMagenta
+	default:
+		return ColorWhite
+	}
+}
+
+// Heading returns the colored line that introduces a section
+func (k SectionKind) Heading() string {
+	if accessible {
+		return i18n.T("a11y.section_start", k.SpokenName())
+	}
+	return Colorize(k.Color(), "--- "+k.Title()+" ---")
+}
+
+// Section is one block of lesson content
+type Section struct {
+	Kind SectionKind
+	Text string
+	// Callouts are the markers stripped from a code section, see
+	// ExtractCallouts
+	Callouts []Callout
+}
+
+// sectionMarker prefixes the lines written to the captured stdout to mark
+// where a new section starts. It never reaches the terminal.
+const sectionMarker = "\x00section:"
+
+// capturing is true while Capture is recording a lesson
+var capturing bool
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// StripANSI removes ANSI escape sequences from s
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// Capture runs a lesson function and collects everything it prints into
+// sections instead of writing it to the terminal. Text printed by the lesson
+// itself (the output of the example code) lands in the section that is open
+// at the time, which is normally the OUTPUT section.
+func Capture(fn func()) []Section {
+	r, w, err := os.Pipe()
+	if err != nil {
+		// Without a pipe we cannot capture; run the lesson as-is
+		fn()
+		return nil
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	capturing = true
+
+	done := make(chan []byte)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		done <- buf.Bytes()
+	}()
+
+	func() {
+		defer func() {
+			capturing = false
+			os.Stdout = stdout
+			w.Close()
+		}()
+		fn()
+	}()
+
+	raw := <-done
+	r.Close()
+	return splitSections(string(raw))
+}
+
+// beginSection starts a new section, either by printing its heading or, while
+// capturing, by writing a marker that splitSections picks up
+func beginSection(kind SectionKind) {
+	if capturing {
+		os.Stdout.WriteString(sectionMarker + strconv.Itoa(int(kind)) + "\n")
+		return
+	}
+	os.Stdout.WriteString(kind.Heading() + "\n")
+}
+
+func splitSections(raw string) []Section {
+	var sections []Section
+	var current *Section
+	var text strings.Builder
+
+	flush := func() {
+		if current != nil {
+			current.Text = strings.Trim(text.String(), "\n")
+			sections = append(sections, *current)
+		}
+		text.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		if strings.HasPrefix(line, sectionMarker) {
+			flush()
+			kind, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, sectionMarker)))
+			current = &Section{Kind: SectionKind(kind)}
+			continue
+		}
+		if current == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			// Text printed before any section is treated as output
+			current = &Section{Kind: SectionOutput}
+		}
+		text.WriteString(line)
+	}
+	flush()
+
+	return sections
+}
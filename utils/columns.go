@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SideBySideWidth is the narrowest terminal on which a lesson's code and
+// its output are shown next to each other instead of one after the other
+const SideBySideWidth = 160
+
+// columnGutter separates the code column from the output column
+const columnGutter = " │ "
+
+// sideBySide lays out a code section and the output section that follows
+// it in two columns of the given total width. Both headings start on the
+// same row and long lines wrap inside their column.
+func sideBySide(code, output Section, width int) []string {
+	leftWidth := (width - utf8.RuneCountInString(columnGutter)) / 2
+	rightWidth := width - leftWidth - utf8.RuneCountInString(columnGutter)
+
+	var left, right []string
+	left = append(left, code.Kind.Heading())
+	for _, line := range codeLines(code, showLineNumbers) {
+		left = append(left, wrapVisible(expandTabs(line), leftWidth)...)
+	}
+	right = append(right, output.Kind.Heading())
+	for _, line := range strings.Split(output.Text, "\n") {
+		right = append(right, wrapVisible(expandTabs(line), rightWidth)...)
+	}
+
+	rows := len(left)
+	if len(right) > rows {
+		rows = len(right)
+	}
+	lines := make([]string, rows)
+	for i := range lines {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		pad := leftWidth - utf8.RuneCountInString(StripANSI(l))
+		if pad < 0 {
+			pad = 0
+		}
+		lines[i] = strings.TrimRight(l+strings.Repeat(" ", pad)+Colorize(ColorGray, columnGutter)+r, " ")
+	}
+	return lines
+}
+
+// expandTabs replaces tabs with four spaces so column widths can be
+// counted in runes
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", "    ")
+}
+
+// wrapVisible soft-wraps a line that may contain ANSI color sequences into
+// pieces of at most width visible characters. A color that is active at a
+// break is closed before it and opened again after it.
+func wrapVisible(line string, width int) []string {
+	if width <= 0 || utf8.RuneCountInString(StripANSI(line)) <= width {
+		return []string{line}
+	}
+
+	var pieces []string
+	var b strings.Builder
+	active := ""
+	visible := 0
+	for i := 0; i < len(line); {
+		if seq := ansiPattern.FindString(line[i:]); seq != "" && strings.HasPrefix(line[i:], seq) {
+			b.WriteString(seq)
+			if seq == ColorReset {
+				active = ""
+			} else {
+				active = seq
+			}
+			i += len(seq)
+			continue
+		}
+		if visible == width {
+			if active != "" {
+				b.WriteString(ColorReset)
+			}
+			pieces = append(pieces, b.String())
+			b.Reset()
+			b.WriteString(active)
+			visible = 0
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+	return append(pieces, b.String())
+}
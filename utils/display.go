@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"os"
+	"strings"
 )
 
 // ANSI color codes
@@ -14,38 +16,77 @@ const (
 	ColorMagenta = "\033[35m"
 	ColorCyan    = "\033[36m"
 	ColorWhite   = "\033[37m"
+	ColorGray    = "\033[90m"
 )
 
-// PrintColoredTitle prints a title in the specified color
+// Display settings, changed through SetColors and SetLineNumbers
+var (
+	colorsEnabled   = os.Getenv("NO_COLOR") == ""
+	showLineNumbers = false
+)
+
+// SetColors turns ANSI colors on or off for everything printed by this package
+func SetColors(enabled bool) {
+	colorsEnabled = enabled
+}
+
+// SetLineNumbers controls whether code examples are printed with line numbers
+func SetLineNumbers(enabled bool) {
+	showLineNumbers = enabled
+}
+
+// Colorize wraps text in the given color as the selected theme renders it,
+// or returns it unchanged when colors are disabled
+func Colorize(color string, text string) string {
+	if !colorsEnabled || text == "" {
+		return text
+	}
+	if color = themed(color); color == "" {
+		return text
+	}
+	return color + text + ColorReset
+}
+
+// PrintColoredTitle prints a title in the specified color, between two
+// rules unless the accessible rendering is selected
 func PrintColoredTitle(title string, color string) {
-	fmt.Println(color + "===================================" + ColorReset)
-	fmt.Println(color + title + ColorReset)
-	fmt.Println(color + "===================================" + ColorReset)
+	if accessible {
+		fmt.Println(title)
+		return
+	}
+	fmt.Println(Colorize(color, "==================================="))
+	fmt.Println(Colorize(color, title))
+	fmt.Println(Colorize(color, "==================================="))
 }
 
 // PrintExplanation prints an explanation block
 func PrintExplanation(text string) {
-	fmt.Println(ColorBlue + "--- EXPLANATION ---" + ColorReset)
+	beginSection(SectionExplanation)
 	fmt.Println(text)
 	fmt.Println()
 }
 
-// PrintCode prints a code example
+// PrintCode prints a code example with Go syntax highlighting
 func PrintCode(code string) {
-	fmt.Println(ColorGreen + "--- CODE EXAMPLE ---" + ColorReset)
-	fmt.Println(code)
+	beginSection(SectionCode)
+	if capturing {
+		// Keep the raw source; it is highlighted when the lesson is rendered
+		fmt.Println(code)
+	} else {
+		fmt.Println(strings.Join(HighlightCode(code, showLineNumbers), "\n"))
+	}
 	fmt.Println()
 }
 
 // PrintOutput prints the output of running the code
 func PrintOutput(text string) {
-	fmt.Println(ColorYellow + "--- OUTPUT ---" + ColorReset)
+	beginSection(SectionOutput)
 	fmt.Println(text)
 	fmt.Println()
 }
 
 // PrintKey prints key takeaways
 func PrintKey(text string) {
-	fmt.Println(ColorMagenta + "--- KEY TAKEAWAYS ---" + ColorReset)
+	beginSection(SectionTakeaways)
 	fmt.Println(text)
 }
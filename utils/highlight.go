@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"go-interface-enum-explorer/i18n"
+)
+
+// TokenClass says how a piece of Go source should be highlighted
+type TokenClass int
+
+const (
+	TokenPlain TokenClass = iota
+	TokenKeyword
+	TokenType
+	TokenBuiltin
+	TokenString
+	TokenNumber
+	TokenComment
+)
+
+// Name returns a short lowercase name for the class, suitable as a CSS class
+func (c TokenClass) Name() string {
+	switch c {
+	case TokenKeyword:
+		return "keyword"
+	case TokenType:
+		return "type"
+	case TokenBuiltin:
+		return "builtin"
+	case TokenString:
+		return "string"
+	case TokenNumber:
+		return "number"
+	case TokenComment:
+		return "comment"
+	default:
+		return "plain"
+	}
+}
+
+// Color returns the ANSI color used for the class in the terminal
+func (c TokenClass) Color() string {
+	switch c {
+	case TokenKeyword:
+		return ColorMagenta
+	case TokenType:
+		return ColorCyan
+	case TokenBuiltin:
+		return ColorBlue
+	case TokenString:
+		return ColorYellow
+	case TokenNumber:
+		return ColorRed
+	case TokenComment:
+		return ColorGray
+	default:
+		return ""
+	}
+}
+
+// Token is a run of source text that shares one highlighting class
+type Token struct {
+	Class TokenClass
+	Text  string
+}
+
+// Predeclared identifiers that get their own color
+var (
+	predeclaredTypes = map[string]bool{
+		"any": true, "bool": true, "byte": true, "comparable": true,
+		"complex64": true, "complex128": true, "error": true,
+		"float32": true, "float64": true, "int": true, "int8": true,
+		"int16": true, "int32": true, "int64": true, "rune": true,
+		"string": true, "uint": true, "uint8": true, "uint16": true,
+		"uint32": true, "uint64": true, "uintptr": true,
+	}
+	predeclaredBuiltins = map[string]bool{
+		"iota": true, "nil": true, "true": true, "false": true,
+		"append": true, "cap": true, "close": true, "complex": true,
+		"copy": true, "delete": true, "imag": true, "len": true,
+		"make": true, "new": true, "panic": true, "print": true,
+		"println": true, "real": true, "recover": true,
+	}
+)
+
+// TokenizeGo splits Go source into highlighted tokens using go/scanner.
+// Concatenating the Text of every token gives back src unchanged, so the
+// result can be rendered for the terminal or as HTML. Snippets do not need
+// to be complete files.
+func TokenizeGo(src string) []Token {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, []byte(src), func(token.Position, string) {}, scanner.ScanComments)
+
+	type span struct {
+		offset int
+		tok    token.Token
+		text   string
+	}
+	var spans []span
+	declared := make(map[string]bool)
+	prev := token.ILLEGAL
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			// Automatically inserted semicolon; not part of the text
+			continue
+		}
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+		if tok == token.IDENT && prev == token.TYPE {
+			declared[lit] = true
+		}
+		spans = append(spans, span{offset: file.Offset(pos), tok: tok, text: text})
+		prev = tok
+	}
+
+	var tokens []Token
+	add := func(class TokenClass, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(tokens); n > 0 && tokens[n-1].Class == class {
+			tokens[n-1].Text += text
+			return
+		}
+		tokens = append(tokens, Token{Class: class, Text: text})
+	}
+
+	offset := 0
+	for _, sp := range spans {
+		if sp.offset < offset {
+			continue
+		}
+		end := sp.offset + len(sp.text)
+		if end > len(src) {
+			end = len(src)
+		}
+		add(TokenPlain, src[offset:sp.offset])
+		add(classify(sp.tok, sp.text, declared), src[sp.offset:end])
+		offset = end
+	}
+	add(TokenPlain, src[offset:])
+
+	return tokens
+}
+
+func classify(tok token.Token, text string, declared map[string]bool) TokenClass {
+	switch {
+	case tok.IsKeyword():
+		return TokenKeyword
+	case tok == token.COMMENT:
+		return TokenComment
+	case tok == token.STRING || tok == token.CHAR:
+		return TokenString
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return TokenNumber
+	case tok == token.IDENT && (predeclaredTypes[text] || declared[text]):
+		return TokenType
+	case tok == token.IDENT && predeclaredBuiltins[text]:
+		return TokenBuiltin
+	default:
+		return TokenPlain
+	}
+}
+
+// HighlightLines tokenizes Go source and splits the tokens into lines, so
+// tokens spanning several lines (such as block comments) are colored on
+// every line they cover
+func HighlightLines(src string) [][]Token {
+	lines := [][]Token{nil}
+	for _, tok := range TokenizeGo(src) {
+		parts := strings.Split(tok.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				last := len(lines) - 1
+				lines[last] = append(lines[last], Token{Class: tok.Class, Text: part})
+			}
+		}
+	}
+	return lines
+}
+
+// HighlightCode returns Go source colored for the terminal, one string per
+// line, optionally prefixed with line numbers. Without colors the lines are
+// returned as plain text.
+func HighlightCode(code string, lineNumbers bool) []string {
+	lines := HighlightLines(code)
+	width := len(fmt.Sprint(len(lines)))
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		var b strings.Builder
+		if accessible {
+			// Read out as "Line 3: ..." rather than a bare number
+			var text strings.Builder
+			for _, tok := range line {
+				text.WriteString(tok.Text)
+			}
+			out[i] = i18n.T("a11y.line", i+1, text.String())
+			continue
+		}
+		if lineNumbers {
+			b.WriteString(Colorize(ColorGray, fmt.Sprintf("%*d | ", width, i+1)))
+		}
+		for _, tok := range line {
+			b.WriteString(Colorize(tok.Class.Color(), tok.Text))
+		}
+		out[i] = b.String()
+	}
+	return out
+}
@@ -3,21 +3,52 @@ package utils
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"go-interface-enum-explorer/i18n"
 )
 
+// stdin is shared by every prompt so that input typed ahead (or piped in)
+// is not swallowed by one reader's buffer and lost to the next
+var stdin = bufio.NewReader(os.Stdin)
+
+// lineReader hands out standard input one line at a time, so a
+// bufio.Scanner layered on top never buffers input meant for another prompt
+type lineReader struct {
+	rest []byte
+}
+
+func (r *lineReader) Read(p []byte) (int, error) {
+	if len(r.rest) == 0 {
+		line, err := stdin.ReadBytes('\n')
+		if len(line) == 0 {
+			return 0, err
+		}
+		r.rest = line
+	}
+	n := copy(p, r.rest)
+	r.rest = r.rest[n:]
+	return n, nil
+}
+
+// Stdin returns a reader over standard input that can be wrapped in a
+// bufio.Scanner and used alongside the prompts in this package
+func Stdin() io.Reader {
+	return &lineReader{}
+}
+
 // PressEnterToContinue pauses execution until the user presses Enter
 func PressEnterToContinue() {
-	fmt.Print("\nPress Enter to continue...")
-	bufio.NewReader(os.Stdin).ReadBytes('\n')
+	fmt.Print("\n" + i18n.T("prompt.continue"))
+	stdin.ReadBytes('\n')
 }
 
 // GetUserInput prompts the user and returns their input as a string
 func GetUserInput(prompt string) string {
-	reader := bufio.NewReader(os.Stdin)
 	fmt.Print(prompt)
-	input, _ := reader.ReadString('\n')
+	input, _ := stdin.ReadString('\n')
 	return strings.TrimSpace(input)
 }
 
@@ -0,0 +1,340 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"go-interface-enum-explorer/i18n"
+)
+
+// Document is a lesson laid out as terminal lines, together with the line
+// each section starts on so the pager can jump between them
+type Document struct {
+	Lines   []string
+	Anchors map[SectionKind]int
+}
+
+// RenderLesson lays out a titled lesson the same way the Print* helpers
+// would print it, except that on terminals at least SideBySideWidth columns
+// wide a code section and the output after it are placed in two columns.
+// Callout markers in the code become numbered badges that the explanation
+// and takeaways refer to by line.
+func RenderLesson(title string, color string, sections []Section) *Document {
+	sections = resolveCallouts(sections)
+	if accessible {
+		return renderAccessible(title, sections)
+	}
+	doc := &Document{Anchors: make(map[SectionKind]int)}
+	doc.add(Colorize(color, "==================================="))
+	doc.add(Colorize(color, title))
+	doc.add(Colorize(color, "==================================="))
+
+	_, cols := TerminalSize()
+	for i := 0; i < len(sections); i++ {
+		s := sections[i]
+		if _, seen := doc.Anchors[s.Kind]; !seen {
+			doc.Anchors[s.Kind] = len(doc.Lines)
+		}
+
+		// On wide terminals code and the output it produces sit side by side
+		if s.Kind == SectionCode && cols >= SideBySideWidth && i+1 < len(sections) && sections[i+1].Kind == SectionOutput {
+			if _, seen := doc.Anchors[SectionOutput]; !seen {
+				doc.Anchors[SectionOutput] = len(doc.Lines)
+			}
+			doc.add(sideBySide(s, sections[i+1], cols)...)
+			doc.add("")
+			i++
+			continue
+		}
+
+		doc.add(s.Kind.Heading())
+		if s.Kind == SectionCode {
+			doc.add(codeLines(s, showLineNumbers)...)
+		} else {
+			doc.add(strings.Split(s.Text, "\n")...)
+		}
+		doc.add("")
+	}
+	return doc
+}
+
+func (d *Document) add(lines ...string) {
+	d.Lines = append(d.Lines, lines...)
+}
+
+// pagerCommand selects how documents taller than the terminal are shown:
+// "" or "builtin" for the built-in pager, "off" to print them in one piece,
+// or an external command such as "less -R" that reads the document from
+// its standard input
+var pagerCommand = ""
+
+// SetPager changes how long documents are shown, see pagerCommand
+func SetPager(command string) {
+	pagerCommand = strings.TrimSpace(command)
+}
+
+// Show prints the document, paging it when it does not fit on the terminal
+func (d *Document) Show() {
+	rows, cols := TerminalSize()
+	p := &pager{doc: d, rows: rows, cols: cols, match: -1, in: stdin, out: os.Stdout}
+	if accessible || pagerCommand == "off" || p.height(0, len(d.Lines)) <= p.pageRows() {
+		for _, line := range d.Lines {
+			fmt.Println(line)
+		}
+		return
+	}
+	if pagerCommand != "" && pagerCommand != "builtin" {
+		if err := d.pipeTo(pagerCommand); err == nil {
+			return
+		}
+		// The command is missing or failed; fall back to the built-in pager
+	}
+	p.run()
+}
+
+// pipeTo shows the document with an external pager command
+func (d *Document) pipeTo(command string) error {
+	args := strings.Fields(command)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(strings.Join(d.Lines, "\n") + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// pager keeps the state of an interactive paging session
+type pager struct {
+	doc     *Document
+	rows    int
+	cols    int
+	top     int
+	pattern string
+	match   int // line of the last match, or -1
+	status  string
+	in      *bufio.Reader
+	out     io.Writer
+}
+
+// pageRows is the number of terminal rows available for content; the last
+// row is reserved for the prompt
+func (p *pager) pageRows() int {
+	if p.rows < 2 {
+		return 1
+	}
+	return p.rows - 1
+}
+
+// lineRows returns how many terminal rows a line takes once wrapped
+func (p *pager) lineRows(line string) int {
+	width := utf8.RuneCountInString(StripANSI(line))
+	if width == 0 || p.cols <= 0 {
+		return 1
+	}
+	return (width + p.cols - 1) / p.cols
+}
+
+// height returns the number of terminal rows lines [from, to) occupy
+func (p *pager) height(from, to int) int {
+	total := 0
+	for i := from; i < to; i++ {
+		total += p.lineRows(p.doc.Lines[i])
+	}
+	return total
+}
+
+// bottom returns the index just past the last line visible from top
+func (p *pager) bottom(top int) int {
+	used := 0
+	i := top
+	for ; i < len(p.doc.Lines); i++ {
+		used += p.lineRows(p.doc.Lines[i])
+		if used > p.pageRows() && i > top {
+			break
+		}
+	}
+	return i
+}
+
+// lastTop returns the top line that shows the end of the document
+func (p *pager) lastTop() int {
+	used := 0
+	for i := len(p.doc.Lines) - 1; i >= 0; i-- {
+		used += p.lineRows(p.doc.Lines[i])
+		if used > p.pageRows() {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// scroll moves the view by n lines, staying inside the document
+func (p *pager) scroll(n int) {
+	p.top += n
+	if last := p.lastTop(); p.top > last {
+		p.top = last
+	}
+	if p.top < 0 {
+		p.top = 0
+	}
+}
+
+func (p *pager) run() {
+	for {
+		p.draw()
+		input, err := p.in.ReadString('\n')
+		if err != nil && input == "" {
+			return
+		}
+		if !p.handle(strings.TrimRight(input, "\r\n")) {
+			return
+		}
+	}
+}
+
+// handle executes one pager command and reports whether paging continues
+func (p *pager) handle(cmd string) bool {
+	p.status = ""
+	half := p.pageRows() / 2
+
+	switch {
+	case cmd == "" || cmd == "f" || cmd == " ":
+		if p.bottom(p.top) >= len(p.doc.Lines) {
+			return false
+		}
+		p.scroll(p.bottom(p.top) - p.top)
+	case cmd == "b":
+		p.scroll(-p.pageRows())
+	case cmd == "d":
+		p.scroll(half)
+	case cmd == "u":
+		p.scroll(-half)
+	case cmd == "g":
+		p.top = 0
+	case cmd == "G":
+		p.top = p.lastTop()
+	case strings.HasPrefix(cmd, "/"):
+		// A new pattern is searched from the top of the screen; "/" alone
+		// repeats the last search, as n does
+		if pattern := cmd[1:]; pattern != "" {
+			p.pattern = pattern
+			p.search(1, p.top)
+		} else {
+			p.search(1, p.from()+1)
+		}
+	case cmd == "n":
+		p.search(1, p.from()+1)
+	case cmd == "N":
+		p.search(-1, p.from()-1)
+	case cmd == "e", cmd == "c", cmd == "o", cmd == "k":
+		p.jump(map[string]SectionKind{
+			"e": SectionExplanation,
+			"c": SectionCode,
+			"o": SectionOutput,
+			"k": SectionTakeaways,
+		}[cmd])
+	case cmd == "h" || cmd == "?":
+		p.status = i18n.T("pager.help")
+	case cmd == "q" || cmd == "Q":
+		return false
+	default:
+		p.status = i18n.T("pager.unknown", cmd)
+	}
+	return true
+}
+
+// jump scrolls to the start of a section
+func (p *pager) jump(kind SectionKind) {
+	line, ok := p.doc.Anchors[kind]
+	if !ok {
+		p.status = i18n.T("pager.no_section", strings.ToLower(i18n.T("section.name."+kind.Name())))
+		return
+	}
+	p.top = 0
+	p.scroll(line)
+}
+
+// from returns the line n and N search from: the last match while it is
+// still on screen, so that matches near the end of the document, which
+// cannot be scrolled to the top, are still visited one by one
+func (p *pager) from() int {
+	if p.match >= p.top && p.match < p.bottom(p.top) {
+		return p.match
+	}
+	return p.top
+}
+
+// search looks for the current pattern starting at line from, moving in
+// direction dir, and scrolls the first match to the top of the screen, or
+// as close to it as the end of the document allows
+func (p *pager) search(dir, from int) {
+	if p.pattern == "" {
+		p.status = i18n.T("pager.no_pattern")
+		return
+	}
+	needle := strings.ToLower(p.pattern)
+	for i := from; i >= 0 && i < len(p.doc.Lines); i += dir {
+		if strings.Contains(strings.ToLower(StripANSI(p.doc.Lines[i])), needle) {
+			p.match = i
+			p.top = 0
+			p.scroll(i)
+			return
+		}
+	}
+	p.status = i18n.T("pager.not_found", p.pattern)
+}
+
+func (p *pager) draw() {
+	fmt.Fprint(p.out, "\033[H\033[2J")
+	end := p.bottom(p.top)
+	for _, line := range p.doc.Lines[p.top:end] {
+		fmt.Fprintln(p.out, p.highlight(line))
+	}
+	for used := p.height(p.top, end); used < p.pageRows(); used++ {
+		fmt.Fprintln(p.out, "~")
+	}
+
+	status := p.status
+	if status == "" {
+		percent := end * 100 / len(p.doc.Lines)
+		status = i18n.T("pager.status", p.top+1, end, len(p.doc.Lines), percent)
+		if end >= len(p.doc.Lines) {
+			status = i18n.T("pager.status_end", p.top+1, end, len(p.doc.Lines))
+		}
+	}
+	fmt.Fprint(p.out, Colorize("\033[7m", status)+" ")
+}
+
+// highlight marks every occurrence of the search pattern in reverse video
+func (p *pager) highlight(line string) string {
+	if p.pattern == "" {
+		return line
+	}
+	plain := StripANSI(line)
+	lower := strings.ToLower(plain)
+	needle := strings.ToLower(p.pattern)
+	if len(lower) != len(plain) {
+		// Case folding changed byte offsets; fall back to an exact match
+		lower, needle = plain, p.pattern
+	}
+	if !strings.Contains(lower, needle) {
+		return line
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 {
+			b.WriteString(plain)
+			break
+		}
+		b.WriteString(plain[:i])
+		b.WriteString("\033[7m" + plain[i:i+len(needle)] + "\033[27m")
+		plain, lower = plain[i+len(needle):], lower[i+len(needle):]
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testDocument has 30 lines, "line 0" to "line 29", with sections starting
+// at lines 0, 8 and 20 and the word needle on lines 10, 27 and 28
+func testDocument() *Document {
+	doc := &Document{Anchors: map[SectionKind]int{
+		SectionExplanation: 0,
+		SectionCode:        8,
+		SectionTakeaways:   20,
+	}}
+	for i := 0; i < 30; i++ {
+		line := fmt.Sprintf("line %d", i)
+		if i == 10 || i == 27 || i == 28 {
+			line += " has a Needle"
+		}
+		doc.add(line)
+	}
+	return doc
+}
+
+// page runs a pager with five rows of content over the test document,
+// reading the commands as if they were typed, and returns it once the
+// input runs out
+func page(t *testing.T, commands ...string) (*pager, string) {
+	t.Helper()
+	var out strings.Builder
+	p := &pager{
+		doc:   testDocument(),
+		rows:  6,
+		cols:  80,
+		match: -1,
+		in:    bufio.NewReader(strings.NewReader(strings.Join(commands, "\n") + "\n")),
+		out:   &out,
+	}
+	p.run()
+	return p, out.String()
+}
+
+func TestPagerSearch(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		commands []string
+		top      int
+		status   string
+	}{
+		{"first match", []string{"/needle"}, 10, ""},
+		{"next match", []string{"/needle", "n"}, 25, ""},
+		// Lines 27 and 28 are both on the last screen, which starts at 25;
+		// n must move on from 27 rather than find it again
+		{"match on the last screen", []string{"/needle", "n", "n"}, 25, ""},
+		{"past the last match", []string{"/needle", "n", "n", "n"}, 25, "Pattern not found: needle"},
+		{"slash repeats the search", []string{"/needle", "/", "/"}, 25, ""},
+		{"previous match", []string{"/needle", "n", "n", "N"}, 25, ""},
+		{"back to the first match", []string{"/needle", "n", "n", "N", "N"}, 10, ""},
+		{"before the first match", []string{"/needle", "N"}, 10, "Pattern not found: needle"},
+		{"from where the view is", []string{"/needle", "g", "d", "d", "n"}, 10, ""},
+		{"no pattern yet", []string{"n"}, 0, "No previous search pattern"},
+		{"no match", []string{"/missing"}, 0, "Pattern not found: missing"},
+	} {
+		p, _ := page(t, tc.commands...)
+		if p.top != tc.top {
+			t.Errorf("%s: top = %d, want %d", tc.name, p.top, tc.top)
+		}
+		if p.status != tc.status {
+			t.Errorf("%s: status = %q, want %q", tc.name, p.status, tc.status)
+		}
+	}
+
+	// Each n on the last screen reports the next match, not the same one
+	p, _ := page(t, "/needle", "n")
+	if p.match != 27 {
+		t.Fatalf("match = %d, want 27", p.match)
+	}
+	p.handle("n")
+	if p.match != 28 {
+		t.Errorf("after n, match = %d, want 28", p.match)
+	}
+}
+
+func TestPagerJumps(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		commands []string
+		top      int
+		status   string
+	}{
+		{"code", []string{"c"}, 8, ""},
+		{"takeaways", []string{"c", "k"}, 20, ""},
+		{"explanation", []string{"k", "e"}, 0, ""},
+		{"missing section", []string{"c", "o"}, 8, "This lesson has no output section"},
+		{"end", []string{"G"}, 25, ""},
+		{"top", []string{"G", "g"}, 0, ""},
+		{"next page", []string{""}, 5, ""},
+		{"page back", []string{"f", "f", "b"}, 5, ""},
+		{"half page", []string{"d", "d", "u"}, 2, ""},
+		{"scrolling stops at the end", []string{"G", "d"}, 25, ""},
+		{"scrolling stops at the top", []string{"u"}, 0, ""},
+		{"help", []string{"h"}, 0, "Enter/f next page"},
+		{"unknown command", []string{"x"}, 0, `Unknown command "x"`},
+	} {
+		p, _ := page(t, tc.commands...)
+		if p.top != tc.top {
+			t.Errorf("%s: top = %d, want %d", tc.name, p.top, tc.top)
+		}
+		if !strings.HasPrefix(p.status, tc.status) || (tc.status == "" && p.status != "") {
+			t.Errorf("%s: status = %q, want %q", tc.name, p.status, tc.status)
+		}
+	}
+}
+
+func TestPagerQuits(t *testing.T) {
+	// q leaves at once; the commands after it are never read
+	p, out := page(t, "q", "G")
+	if p.top != 0 || strings.Count(out, "\033[2J") != 1 {
+		t.Errorf("after q: top = %d and %d screens drawn, want 0 and 1", p.top, strings.Count(out, "\033[2J"))
+	}
+
+	// Enter on the last screen leaves too
+	_, out = page(t, "G", "", "g")
+	if strings.Count(out, "\033[2J") != 2 {
+		t.Errorf("Enter on the last screen drew %d screens, want 2", strings.Count(out, "\033[2J"))
+	}
+	if !strings.Contains(out, "(END) lines 26-30 of 30") {
+		t.Errorf("the last screen has no end status:\n%q", out)
+	}
+}
+
+func TestPagerDraw(t *testing.T) {
+	_, out := page(t, "/needle")
+	screens := strings.Split(out, "\033[H\033[2J")
+	last := screens[len(screens)-1]
+	for _, want := range []string{"line 10 has a \033[7mNeedle\033[27m", "line 14", "lines 11-15 of 30 (50%)"} {
+		if !strings.Contains(last, want) {
+			t.Errorf("the screen after the search does not contain %q:\n%q", want, last)
+		}
+	}
+	if strings.Contains(last, "line 15") {
+		t.Errorf("the screen shows more than five lines:\n%q", last)
+	}
+}
+
+func TestPagerWrapsLongLines(t *testing.T) {
+	p := &pager{doc: &Document{Lines: []string{strings.Repeat("x", 25), "short", ""}}, rows: 6, cols: 10}
+	if got := p.height(0, 3); got != 5 {
+		t.Errorf("height = %d, want 5: three rows for the long line and one for each other", got)
+	}
+}
+
+func TestTerminalSizeFallback(t *testing.T) {
+	if _, _, ok := ioctlSize(os.Stdout.Fd()); ok {
+		t.Skip("stdout is a terminal, so its size is read and the environment is ignored")
+	}
+	for _, tc := range []struct {
+		lines, columns string
+		rows, cols     int
+	}{
+		{"40", "120", 40, 120},
+		{"", "", defaultRows, defaultColumns},
+		{"0", "-5", defaultRows, defaultColumns},
+		{"tall", "100", defaultRows, 100},
+	} {
+		t.Setenv("LINES", tc.lines)
+		t.Setenv("COLUMNS", tc.columns)
+		if rows, cols := TerminalSize(); rows != tc.rows || cols != tc.cols {
+			t.Errorf("LINES=%q COLUMNS=%q: TerminalSize() = %d, %d, want %d, %d",
+				tc.lines, tc.columns, rows, cols, tc.rows, tc.cols)
+		}
+	}
+}
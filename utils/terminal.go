@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+)
+
+// Fallback terminal dimensions used when neither the terminal nor the
+// environment can tell us the real size
+const (
+	defaultRows    = 24
+	defaultColumns = 80
+)
+
+// TerminalSize returns the number of rows and columns of the terminal
+// attached to stdout. The size is read with an ioctl where the platform
+// supports it and falls back to the $LINES and $COLUMNS environment variables.
+func TerminalSize() (rows, cols int) {
+	if r, c, ok := ioctlSize(os.Stdout.Fd()); ok {
+		return r, c
+	}
+
+	rows, cols = defaultRows, defaultColumns
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		rows = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		cols = n
+	}
+	return rows, cols
+}
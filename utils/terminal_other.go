@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package utils
+
+// ioctlSize is not available on this platform; TerminalSize falls back to
+// the environment
+func ioctlSize(fd uintptr) (rows, cols int, ok bool) {
+	return 0, 0, false
+}
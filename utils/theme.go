@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Theme decides the escape sequence printed for each of the Color
+// constants. Code keeps naming colors, and a theme swaps them for shades
+// that read well on a given terminal background.
+type Theme struct {
+	Name        string
+	Description string
+	// Colors maps a Color constant to the sequence used in its place. Colors
+	// that are not listed are printed as they are.
+	Colors map[string]string
+}
+
+// Themes are the named color themes, the first being the default
+var Themes = []Theme{
+	{
+		Name:        "dark",
+		Description: "the standard terminal colors, for dark backgrounds",
+	},
+	{
+		Name:        "light",
+		Description: "darker shades that stay readable on light backgrounds",
+		Colors: map[string]string{
+			ColorRed:     "\033[38;5;124m",
+			ColorGreen:   "\033[38;5;28m",
+			ColorYellow:  "\033[38;5;130m",
+			ColorBlue:    "\033[38;5;25m",
+			ColorMagenta: "\033[38;5;90m",
+			ColorCyan:    "\033[38;5;30m",
+			ColorWhite:   "\033[30m",
+			ColorGray:    "\033[38;5;242m",
+		},
+	},
+	{
+		Name:        "high-contrast",
+		Description: "bold, bright colors",
+		Colors: map[string]string{
+			ColorRed:     "\033[1;91m",
+			ColorGreen:   "\033[1;92m",
+			ColorYellow:  "\033[1;93m",
+			ColorBlue:    "\033[1;94m",
+			ColorMagenta: "\033[1;95m",
+			ColorCyan:    "\033[1;96m",
+			ColorWhite:   "\033[1;97m",
+			ColorGray:    "\033[97m",
+		},
+	},
+	{
+		Name:        "monochrome",
+		Description: "no colors; search matches are still shown in reverse video",
+		Colors: map[string]string{
+			ColorRed:     "",
+			ColorGreen:   "",
+			ColorYellow:  "",
+			ColorBlue:    "",
+			ColorMagenta: "",
+			ColorCyan:    "",
+			ColorWhite:   "",
+			ColorGray:    "",
+		},
+	},
+}
+
+// theme is the selected theme, changed through SetTheme
+var theme = Themes[0]
+
+// SetTheme selects a theme by name
+func SetTheme(name string) error {
+	for _, t := range Themes {
+		if strings.EqualFold(t.Name, name) {
+			theme = t
+			return nil
+		}
+	}
+	var names []string
+	for _, t := range Themes {
+		names = append(names, t.Name)
+	}
+	return fmt.Errorf("unknown theme %q (choose %s)", name, strings.Join(names, ", "))
+}
+
+// ThemeName returns the name of the selected theme
+func ThemeName() string {
+	return theme.Name
+}
+
+// themed returns the sequence the selected theme prints for a color
+func themed(color string) string {
+	if mapped, ok := theme.Colors[color]; ok {
+		return mapped
+	}
+	return color
+}